@@ -37,6 +37,10 @@ func (s *Http) Get(url string, argument interface{}) (output []byte, connState *
 }
 
 func (s *Http) PostJson(url string, argument interface{}, headers ...Header) (input, output []byte, connState *tls.ConnectionState, statusCode int, err error) {
+	return s.RequestJson("POST", url, argument, headers...)
+}
+
+func (s *Http) RequestJson(method, url string, argument interface{}, headers ...Header) (input, output []byte, connState *tls.ConnectionState, statusCode int, err error) {
 	input = nil
 	var body io.Reader = nil
 	if argument != nil {
@@ -55,7 +59,7 @@ func (s *Http) PostJson(url string, argument interface{}, headers ...Header) (in
 		}
 	}
 
-	req, e := http.NewRequest("POST", url, body)
+	req, e := http.NewRequest(method, url, body)
 	if e != nil {
 		err = e
 		return
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"github.com/csby/wsf/doc/generator"
+	"io/ioutil"
+	"net/http"
+	"os"
+)
+
+// 根据运行中服务的接口文档生成Go语言客户端代码, 或导出为Markdown及静态HTML文档, 如:
+// wsfgen -url https://127.0.0.1:8443/doc.api -pkg api -out api/client.go
+// wsfgen -url https://127.0.0.1:8443/doc.api -format html -title 接口文档 -out api.html
+func main() {
+	url := flag.String("url", "", "document api url, e.g. https://127.0.0.1:8443/doc.api")
+	pkg := flag.String("pkg", "api", "package name of generated code")
+	out := flag.String("out", "", "output file path, print to stdout if empty")
+	insecure := flag.Bool("insecure", false, "skip verifying server certificate")
+	format := flag.String("format", "go", "output format: go, markdown or html")
+	title := flag.String("title", "接口文档", "document title for markdown or html format")
+	flag.Parse()
+
+	if len(*url) < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var transport *http.Transport = nil
+	if *insecure {
+		transport = &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}
+	}
+
+	g := generator.NewGenerator(*pkg)
+	err := g.LoadUrl(*url, transport)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "load document fail:", err)
+		os.Exit(1)
+	}
+
+	code := &bytes.Buffer{}
+	switch *format {
+	case "go":
+		err = g.Generate(code)
+	case "markdown", "md":
+		err = g.GenerateMarkdown(code, *title)
+	case "html":
+		err = g.GenerateHtml(code, *title)
+	default:
+		err = fmt.Errorf("unsupported format: %s", *format)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "generate fail:", err)
+		os.Exit(1)
+	}
+
+	if len(*out) < 1 {
+		os.Stdout.Write(code.Bytes())
+		return
+	}
+	err = ioutil.WriteFile(*out, code.Bytes(), 0644)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "write file fail:", err)
+		os.Exit(1)
+	}
+}
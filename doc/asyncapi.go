@@ -0,0 +1,76 @@
+package doc
+
+import (
+	"fmt"
+	"github.com/csby/wsf/doc/model"
+	"github.com/csby/wsf/types"
+	"strings"
+)
+
+func (s *doc) AsyncApi(info *types.OpenApiInfo, format string) ([]byte, error) {
+	return s.marshal(s.asyncApi(info), format)
+}
+
+func (s *doc) asyncApi(info *types.OpenApiInfo) *model.AsyncApi {
+	document := &model.AsyncApi{
+		AsyncApi: model.AsyncApiVersion,
+		Channels: make(map[string]*model.AsyncApiChannel),
+	}
+
+	securitySchemes := make(map[string]*model.OpenApiSecurityScheme)
+	security := make([]map[string][]string, 0)
+	for _, fun := range s.functions {
+		if !fun.WebSocket {
+			continue
+		}
+		document.Channels[fun.OpenApiPath()] = fun.ToAsyncApi()
+
+		name := model.OpenApiSecurityTokenHeader
+		if fun.GetInputQuery(types.TokenName) != nil {
+			name = model.OpenApiSecurityTokenQuery
+		} else if fun.GetInputHeader(types.TokenName) == nil {
+			continue
+		}
+		if _, ok := securitySchemes[name]; !ok {
+			scheme := s.openApiSecurityScheme(name)
+			scheme.Type = "httpApiKey"
+			securitySchemes[name] = scheme
+			security = append(security, map[string][]string{name: {}})
+		}
+	}
+	if len(securitySchemes) > 0 {
+		document.Components = &model.AsyncApiComponents{SecuritySchemes: securitySchemes}
+	}
+
+	if info != nil {
+		document.Info.Title = info.Title
+		document.Info.Description = info.Description
+		document.Info.Version = info.Version
+		for index, server := range info.Servers {
+			if document.Servers == nil {
+				document.Servers = make(map[string]*model.AsyncApiServer)
+			}
+			protocol := "ws"
+			url := server
+			if strings.HasPrefix(url, "https://") {
+				protocol = "wss"
+				url = "wss://" + strings.TrimPrefix(url, "https://")
+			} else if strings.HasPrefix(url, "http://") {
+				url = "ws://" + strings.TrimPrefix(url, "http://")
+			}
+			name := "default"
+			if index > 0 {
+				name = fmt.Sprintf("server%d", index)
+			}
+			document.Servers[name] = &model.AsyncApiServer{
+				Url:      url,
+				Protocol: protocol,
+			}
+			if len(security) > 0 {
+				document.Servers[name].Security = security
+			}
+		}
+	}
+
+	return document
+}
@@ -0,0 +1,48 @@
+package doc
+
+import (
+	"encoding/json"
+	"github.com/csby/wsf/doc/model"
+	"github.com/csby/wsf/types"
+	"testing"
+)
+
+func TestDoc_AsyncApi(t *testing.T) {
+	messages := types.NewSocketMessageRegistry()
+	messages.Register(types.WSOptUserLogin, "用户登陆", types.SocketDirectionServerToClient, &types.OnlineUser{})
+	messages.Register(201, "心跳", types.SocketDirectionBoth, nil)
+
+	d := NewDoc(true)
+	path := &types.Path{Prefix: "/api"}
+	catalog := d.AddCatalog("管理平台接口")
+	fun := catalog.AddFunction("GET", path.New("/notify").SetTokenPlace(types.TokenPlaceQuery).SetTokenType(types.TokenTypeAccountPassword).SetWebSocket(true), "通知推送")
+	messages.Doc(fun)
+	catalog.AddFunction("POST", path.New("/login"), "用户登录")
+
+	data, err := d.AsyncApi(&types.OpenApiInfo{Title: "test", Version: "1.0.1", Servers: []string{"https://127.0.0.1:8443"}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	document := &model.AsyncApi{}
+	err = json.Unmarshal(data, document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(document.Channels) != 1 {
+		t.Fatal("expect 1 channel, but got", len(document.Channels))
+	}
+	channel := document.Channels["/api/notify"]
+	if channel == nil || channel.Subscribe == nil || channel.Publish == nil {
+		t.Fatal("invalid channel:", string(data))
+	}
+	if len(channel.Subscribe.Message.OneOf) != 2 || len(channel.Publish.Message.OneOf) != 1 {
+		t.Fatal("invalid messages:", string(data))
+	}
+	server := document.Servers["default"]
+	if server == nil || server.Url != "wss://127.0.0.1:8443" || server.Protocol != "wss" {
+		t.Fatal("invalid server:", string(data))
+	}
+	if document.Components == nil || document.Components.SecuritySchemes[model.OpenApiSecurityTokenQuery] == nil {
+		t.Fatal("security scheme not exist")
+	}
+}
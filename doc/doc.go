@@ -101,6 +101,24 @@ func (s *doc) TokenCreate(id string, items []types.TokenAuth, a types.Assistant)
 	return create(items, a)
 }
 
+func (s *doc) InputValidator(method, path string) (types.InputValidator, bool) {
+	fun, ok := s.functions[s.generateFunctionId(method, path)]
+	if !ok || fun.WebSocket {
+		return nil, false
+	}
+
+	return fun.ValidateInput, len(fun.InputForms) < 1
+}
+
+func (s *doc) Deprecation(method, path string) *types.Deprecation {
+	fun, ok := s.functions[s.generateFunctionId(method, path)]
+	if !ok {
+		return nil
+	}
+
+	return fun.Deprecation
+}
+
 func (s *doc) onNewFunction(fun *model.Function) {
 	id := s.generateFunctionId(fun.Method, fun.Path)
 	_, ok := s.functions[id]
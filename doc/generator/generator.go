@@ -0,0 +1,160 @@
+package generator
+
+import (
+	"fmt"
+	"github.com/csby/wsf/client"
+	"github.com/csby/wsf/doc/model"
+	"github.com/csby/wsf/types"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// 根据接口文档生成Go语言的客户端代码, 或导出为Markdown及静态HTML文档
+// pkg: 生成代码的包名
+func NewGenerator(pkg string) *Generator {
+	return &Generator{
+		Package:   pkg,
+		functions: make([]*model.Function, 0),
+		catalogs:  make(model.CatalogSlice, 0),
+		items:     make(map[string]*model.Function),
+	}
+}
+
+type Generator struct {
+	Package string // 包名
+
+	functions []*model.Function
+	catalogs  model.CatalogSlice
+	items     map[string]*model.Function
+}
+
+// 从进程内注册的接口文档加载
+func (s *Generator) LoadDoc(doc types.Doc) error {
+	if doc == nil {
+		return fmt.Errorf("invalid doc: nil")
+	}
+
+	catalogs, ok := doc.Catalogs().(model.CatalogSlice)
+	if !ok {
+		return fmt.Errorf("invalid catalogs type: %T", doc.Catalogs())
+	}
+	s.catalogs = append(s.catalogs, catalogs...)
+
+	return s.load(catalogs, func(id string) (*model.Function, error) {
+		v, err := doc.Function(id, "http", "localhost")
+		if err != nil {
+			return nil, err
+		}
+		fun, ok := v.(*model.Function)
+		if !ok {
+			return nil, fmt.Errorf("invalid function type: %T", v)
+		}
+		return fun, nil
+	})
+}
+
+// 从运行中的服务加载
+// url: 接口文档的接口地址, 如: https://127.0.0.1:8443/doc.api
+// transport: 为空时使用默认设置(https时可用于指定证书或忽略证书验证)
+func (s *Generator) LoadUrl(url string, transport *http.Transport) error {
+	c := &client.Http{Transport: transport, Timeout: 30}
+	baseUrl := strings.TrimSuffix(url, "/")
+
+	catalogs := make(model.CatalogSlice, 0)
+	err := s.post(c, baseUrl+"/catalog/tree", &catalogs)
+	if err != nil {
+		return err
+	}
+	s.catalogs = append(s.catalogs, catalogs...)
+
+	return s.load(catalogs, func(id string) (*model.Function, error) {
+		fun := &model.Function{}
+		err := s.post(c, fmt.Sprintf("%s/function/%s", baseUrl, id), fun)
+		if err != nil {
+			return nil, err
+		}
+		return fun, nil
+	})
+}
+
+// 生成代码并写入w
+func (s *Generator) Generate(w io.Writer) error {
+	if len(s.Package) < 1 {
+		return fmt.Errorf("invalid package name: empty")
+	}
+
+	code, err := newGolang(s.Package, s.functions).generate()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(code)
+
+	return err
+}
+
+// 导出为Markdown文档并写入w
+func (s *Generator) GenerateMarkdown(w io.Writer, title string) error {
+	return s.generateStatic(w, &markdownWriter{}, title)
+}
+
+// 导出为独立的静态HTML文档(不依赖外部资源)并写入w
+func (s *Generator) GenerateHtml(w io.Writer, title string) error {
+	return s.generateStatic(w, &htmlWriter{}, title)
+}
+
+func (s *Generator) generateStatic(w io.Writer, writer staticWriter, title string) error {
+	content, err := newStatic(writer, s.catalogs, s.items).generate(title)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(content)
+
+	return err
+}
+
+func (s *Generator) load(catalogs model.CatalogSlice, function func(id string) (*model.Function, error)) error {
+	for _, catalog := range catalogs {
+		if catalog == nil {
+			continue
+		}
+
+		if catalog.Type != model.TypeFunction {
+			err := s.load(catalog.Children, function)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		fun, err := function(catalog.ID)
+		if err != nil {
+			return fmt.Errorf("load function '%s' fail: %v", catalog.Name, err)
+		}
+		s.functions = append(s.functions, fun)
+		s.items[catalog.ID] = fun
+	}
+
+	return nil
+}
+
+func (s *Generator) post(c *client.Http, url string, data interface{}) error {
+	_, output, _, statusCode, err := c.PostJson(url, nil)
+	if err != nil {
+		return err
+	}
+	if statusCode != http.StatusOK {
+		return fmt.Errorf("%s: status code %d", url, statusCode)
+	}
+
+	result := &types.Result{}
+	err = result.Unmarshal(output)
+	if err != nil {
+		return err
+	}
+	if result.Code != 0 {
+		return fmt.Errorf("%s: %d-%s %s", url, result.Code, result.Error.Summary, result.Error.Detail)
+	}
+
+	return result.GetData(data)
+}
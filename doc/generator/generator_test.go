@@ -0,0 +1,163 @@
+package generator
+
+import (
+	"bytes"
+	"github.com/csby/wsf/doc"
+	"github.com/csby/wsf/types"
+	"strings"
+	"testing"
+)
+
+func TestGenerator_Generate(t *testing.T) {
+	d := doc.NewDoc(true)
+	path := &types.Path{Prefix: "/opt.api"}
+	catalog := d.AddCatalog("管理平台接口")
+
+	fun := catalog.AddFunction("POST", path.New("/login"), "用户登录")
+	fun.SetInputExample(&types.LoginFilter{Account: "admin"})
+	fun.SetOutputDataExample(&types.Login{Token: "71b9b7e2ac6d4166b18f414942ff3481"})
+	fun.AddOutputError(types.ErrLoginPasswordInvalid)
+
+	fun = catalog.AddFunction("POST", path.New("/online/users").SetTokenType(types.TokenTypeAccountPassword), "获取在线用户")
+	fun.SetOutputDataExample([]types.OnlineUser{{UserAccount: "admin"}})
+
+	fun = catalog.AddFunction("DELETE", path.New("/user/:id").SetTokenType(types.TokenTypeAccountPassword), "删除用户")
+	fun.SetOutputDataExample(nil)
+
+	fun = catalog.AddFunction("GET", path.New("/log/file/download").SetTokenType(types.TokenTypeAccountPassword).SetTokenPlace(types.TokenPlaceQuery), "下载日志文件")
+	fun.AddInputQuery(true, "name", "文件名称", "")
+
+	g := NewGenerator("api")
+	err := g.LoadDoc(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := &bytes.Buffer{}
+	err = g.Generate(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := code.String()
+	expects := []string{
+		"package api",
+		"func (s *Client) Login(argument *LoginFilter) (*Login, error) {",
+		"func (s *Client) OnlineUsers() ([]OnlineUser, error) {",
+		`func (s *Client) User(id string) error {`,
+		`"/opt.api/user/"+url.PathEscape(id)`,
+		"func (s *Client) LogFileDownload(name string) ([]byte, error) {",
+		"ErrorCode203 ErrorCode = 203 // 密码不正确",
+		"type LoginFilter struct {",
+		"type OnlineUser struct {",
+	}
+	for _, expect := range expects {
+		if !strings.Contains(text, expect) {
+			t.Fatal("'", expect, "' not found in code:\n", text)
+		}
+	}
+}
+
+// 与types.Login同名
+type Login struct {
+	Account string `json:"account"`
+	Role    string `json:"role"`
+}
+
+type 用户 struct {
+	Name string `json:"名称"`
+}
+
+func TestGenerator_GenerateNames(t *testing.T) {
+	d := doc.NewDoc(true)
+	path := &types.Path{Prefix: "/opt.api"}
+	catalog := d.AddCatalog("管理平台接口")
+
+	fun := catalog.AddFunction("POST", path.New("/login"), "用户登录")
+	fun.SetInputExample(&Login{Account: "admin"})
+	fun.SetOutputDataExample(&types.Login{Token: "71b9b7e2ac6d4166b18f414942ff3481"})
+
+	fun = catalog.AddFunction("POST", path.New("/user/用户"), "用户信息")
+	fun.SetOutputDataExample(&用户{Name: "admin"})
+
+	g := NewGenerator("api")
+	err := g.LoadDoc(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := &bytes.Buffer{}
+	err = g.Generate(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := code.String()
+	expects := []string{
+		"func (s *Client) Login(argument *Login) (*TypesLogin, error) {",
+		"type Login struct {",
+		"type TypesLogin struct {",
+		"func (s *Client) User() (*Model, error) {",
+		"type Model struct {",
+		"Field string `json:\"名称\"`",
+	}
+	for _, expect := range expects {
+		if !strings.Contains(text, expect) {
+			t.Fatal("'", expect, "' not found in code:\n", text)
+		}
+	}
+}
+
+func TestGenerator_GenerateStatic(t *testing.T) {
+	d := doc.NewDoc(true)
+	path := &types.Path{Prefix: "/opt.api"}
+	catalog := d.AddCatalog("管理平台接口").AddChild("权限管理")
+
+	fun := catalog.AddFunction("POST", path.New("/login"), "用户登录")
+	fun.SetNote("通过账号及密码登录")
+	fun.SetInputExample(&types.LoginFilter{Account: "admin"})
+	fun.SetOutputDataExample(&types.Login{Token: "71b9b7e2ac6d4166b18f414942ff3481"})
+	fun.AddOutputError(types.ErrLoginPasswordInvalid)
+
+	g := NewGenerator("api")
+	err := g.LoadDoc(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content := &bytes.Buffer{}
+	err = g.GenerateMarkdown(content, "接口文档")
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := content.String()
+	expects := []string{
+		"# 接口文档",
+		"  - [用户登录](#fn-",
+		"| 地址 | /opt.api/login |",
+		"| account | string |",
+		"| 203 | 密码不正确 |",
+		`"account": "admin"`,
+	}
+	for _, expect := range expects {
+		if !strings.Contains(text, expect) {
+			t.Fatal("'", expect, "' not found in markdown:\n", text)
+		}
+	}
+
+	content.Reset()
+	err = g.GenerateHtml(content, "接口文档<1>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	text = content.String()
+	expects = []string{
+		"<title>接口文档&lt;1&gt;</title>",
+		"<h4 id=\"fn-",
+		"<td>/opt.api/login</td>",
+		"&#34;account&#34;: &#34;admin&#34;",
+	}
+	for _, expect := range expects {
+		if !strings.Contains(text, expect) {
+			t.Fatal("'", expect, "' not found in html:\n", text)
+		}
+	}
+}
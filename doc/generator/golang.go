@@ -0,0 +1,628 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/csby/wsf/doc/model"
+	"go/format"
+	"go/token"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	tokenPlaceHeader = "header"
+	tokenPlaceQuery  = "query"
+)
+
+var (
+	// 生成代码中已使用的类型名称
+	reservedNames = map[string]bool{
+		"Client":    true,
+		"NewClient": true,
+		"Error":     true,
+		"ErrorCode": true,
+	}
+)
+
+func newGolang(pkg string, functions []*model.Function) *golang {
+	return &golang{
+		pkg:         pkg,
+		functions:   functions,
+		structs:     make([]*golangStruct, 0),
+		structMap:   make(map[string]*golangStruct),
+		structNames: make(map[string]bool),
+		errorCodes:  make(map[int][]string),
+		imports:     make(map[string]bool),
+	}
+}
+
+type golangStruct struct {
+	name  string
+	model *model.Model
+}
+
+type golang struct {
+	pkg         string
+	functions   []*model.Function
+	structs     []*golangStruct
+	structMap   map[string]*golangStruct // 包路径.类型名称 -> 结构体
+	structNames map[string]bool
+	errorCodes  map[int][]string
+	imports     map[string]bool
+	sb          strings.Builder
+}
+
+func (s *golang) generate() ([]byte, error) {
+	for _, fun := range s.functions {
+		s.addModels(fun.InputModel)
+		s.addModels(fun.OutputModel)
+		for _, item := range fun.OutputErrors {
+			s.addErrorCode(item.Code, item.Summary)
+		}
+	}
+
+	s.writeClient()
+	s.writeFunctions()
+	s.writeErrorCodes()
+	s.writeStructs()
+
+	// 导入的包在生成类型时确定
+	body := s.sb.String()
+	s.sb.Reset()
+	s.writeHeader()
+	s.sb.WriteString(body)
+
+	code := []byte(s.sb.String())
+	formatted, err := format.Source(code)
+	if err != nil {
+		return code, fmt.Errorf("format code fail: %v", err)
+	}
+
+	return formatted, nil
+}
+
+func (s *golang) addModels(models []*model.Model) {
+	for _, item := range models {
+		if item == nil || len(item.Children) < 1 {
+			continue
+		}
+		name := s.baseTypeName(item.Name)
+		if name == "Result" || name == "ResultError" || len(name) < 1 || strings.Contains(name, "[]") {
+			continue
+		}
+		key := s.structKey(item.Package, name)
+		if _, ok := s.structMap[key]; ok {
+			continue
+		}
+
+		st := &golangStruct{name: s.structName(item.Package, name), model: item}
+		s.structMap[key] = st
+		s.structs = append(s.structs, st)
+	}
+}
+
+func (s *golang) structKey(pkg, name string) string {
+	return pkg + "." + s.baseTypeName(name)
+}
+
+// 结构体名称: 不同包中的同名类型以包名区分, 非ASCII名称使用Model
+func (s *golang) structName(pkg, name string) string {
+	goName := s.identifier(name)
+	if len(goName) < 1 {
+		goName = "Model"
+	}
+	if reservedNames[goName] {
+		goName += "Model"
+	}
+	if s.structNames[goName] && len(pkg) > 0 {
+		goName = s.identifier(path.Base(pkg)) + goName
+	}
+	base := goName
+	for index := 2; s.structNames[goName]; index++ {
+		goName = fmt.Sprintf("%s%d", base, index)
+	}
+	s.structNames[goName] = true
+
+	return goName
+}
+
+func (s *golang) addErrorCode(code int, summary string) {
+	if code == 0 {
+		return
+	}
+	summaries := s.errorCodes[code]
+	for _, item := range summaries {
+		if item == summary {
+			return
+		}
+	}
+	s.errorCodes[code] = append(summaries, summary)
+}
+
+func (s *golang) writeHeader() {
+	s.line("// Code generated by wsf generator. DO NOT EDIT.")
+	s.line("")
+	s.line("package ", s.pkg)
+	s.line("")
+	s.line("import (")
+	imports := []string{"fmt", "net/http", "net/url", "github.com/csby/wsf/client", "github.com/csby/wsf/types"}
+	for k := range s.imports {
+		imports = append(imports, k)
+	}
+	sort.Strings(imports)
+	for _, item := range imports {
+		s.line(strconv.Quote(item))
+	}
+	s.line(")")
+	s.line("")
+}
+
+func (s *golang) writeClient() {
+	s.line("// baseUrl: 服务地址, 如: https://127.0.0.1:8443")
+	s.line("func NewClient(baseUrl string) *Client {")
+	s.line("return &Client{BaseUrl: baseUrl}")
+	s.line("}")
+	s.line("")
+	s.line("type Client struct {")
+	s.line("BaseUrl string // 服务地址")
+	s.line("Token string // 接口访问凭证")
+	s.line("Http client.Http")
+	s.line("}")
+	s.line("")
+	s.line("func (s *Client) request(method, path string, query url.Values, tokenPlace string, argument interface{}) ([]byte, error) {")
+	s.line("headers := make([]client.Header, 0)")
+	s.line("if tokenPlace == ", strconv.Quote(tokenPlaceHeader), " {")
+	s.line("headers = append(headers, client.Header{Key: types.TokenName, Value: s.Token})")
+	s.line("} else if tokenPlace == ", strconv.Quote(tokenPlaceQuery), " {")
+	s.line("if query == nil {")
+	s.line("query = url.Values{}")
+	s.line("}")
+	s.line("query.Set(types.TokenName, s.Token)")
+	s.line("}")
+	s.line("address := s.BaseUrl + path")
+	s.line("if len(query) > 0 {")
+	s.line(`address += "?" + query.Encode()`)
+	s.line("}")
+	s.line("")
+	s.line("_, output, _, statusCode, err := s.Http.RequestJson(method, address, argument, headers...)")
+	s.line("if err != nil {")
+	s.line("return nil, err")
+	s.line("}")
+	s.line("if statusCode != http.StatusOK {")
+	s.line(`return nil, fmt.Errorf("%s %s: status code %d", method, path, statusCode)`)
+	s.line("}")
+	s.line("")
+	s.line("return output, nil")
+	s.line("}")
+	s.line("")
+	s.line("func (s *Client) do(method, path string, query url.Values, tokenPlace string, argument, data interface{}) error {")
+	s.line("output, err := s.request(method, path, query, tokenPlace, argument)")
+	s.line("if err != nil {")
+	s.line("return err")
+	s.line("}")
+	s.line("")
+	s.line("result := &types.Result{}")
+	s.line("err = result.Unmarshal(output)")
+	s.line("if err != nil {")
+	s.line("return err")
+	s.line("}")
+	s.line("if result.Code != 0 {")
+	s.line("return &Error{Code: ErrorCode(result.Code), Summary: result.Error.Summary, Detail: result.Error.Detail}")
+	s.line("}")
+	s.line("if data == nil {")
+	s.line("return nil")
+	s.line("}")
+	s.line("")
+	s.line("return result.GetData(data)")
+	s.line("}")
+	s.line("")
+}
+
+func (s *golang) writeFunctions() {
+	names := s.functionNames()
+	for index, fun := range s.functions {
+		if fun.WebSocket || len(fun.InputForms) > 0 {
+			s.line("// ", names[index], ": ", s.comment(fun.Name), "(", fun.Method, " ", fun.Path, ")暂不支持生成")
+			s.line("")
+			continue
+		}
+		s.writeFunction(names[index], fun)
+	}
+}
+
+func (s *golang) writeFunction(name string, fun *model.Function) {
+	params := make([]string, 0)
+	pathExpr := make([]string, 0)
+	literal := ""
+	for index, segment := range strings.Split(fun.Path, "/") {
+		if index > 0 {
+			literal += "/"
+		}
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			param := s.paramName(segment[1:])
+			params = append(params, param+" string")
+			pathExpr = append(pathExpr, strconv.Quote(literal))
+			if strings.HasPrefix(segment, "*") {
+				pathExpr = append(pathExpr, param)
+			} else {
+				pathExpr = append(pathExpr, "url.PathEscape("+param+")")
+			}
+			literal = ""
+			continue
+		}
+		literal += segment
+	}
+	if len(literal) > 0 {
+		pathExpr = append(pathExpr, strconv.Quote(literal))
+	}
+
+	tokenPlace := ""
+	queries := make([]*model.Query, 0)
+	for _, item := range fun.InputHeaders {
+		if item != nil && item.Token {
+			tokenPlace = tokenPlaceHeader
+		}
+	}
+	for _, item := range fun.InputQueries {
+		if item == nil {
+			continue
+		}
+		if item.Token {
+			tokenPlace = tokenPlaceQuery
+			continue
+		}
+		queries = append(queries, item)
+		params = append(params, s.paramName(item.Name)+" string")
+	}
+
+	argument := "nil"
+	if fun.InputSample != nil {
+		params = append(params, "argument "+s.inputType(fun))
+		argument = "argument"
+	}
+
+	// 未定义输出示例时(如文件下载)返回原始内容
+	raw := true
+	output := ""
+	if fun.OutputSample != nil {
+		output, raw = s.outputType(fun)
+	}
+
+	s.line("// ", s.comment(fun.Name))
+	if len(fun.Note) > 0 {
+		s.line("// ", s.comment(fun.Note))
+	}
+	if len(fun.OutputErrors) > 0 {
+		codes := make([]string, 0)
+		for _, item := range fun.OutputErrors {
+			codes = append(codes, s.errorCodeName(item.Code))
+		}
+		s.line("// 错误代码: ", strings.Join(codes, ", "))
+	}
+	if fun.Deprecation != nil {
+		s.line("//")
+		s.line("// Deprecated: ", s.comment(fun.DeprecationText()))
+	}
+	if raw {
+		s.line("func (s *Client) ", name, "(", strings.Join(params, ", "), ") ([]byte, error) {")
+	} else if output == "" {
+		s.line("func (s *Client) ", name, "(", strings.Join(params, ", "), ") error {")
+	} else {
+		s.line("func (s *Client) ", name, "(", strings.Join(params, ", "), ") (", output, ", error) {")
+	}
+
+	query := "nil"
+	if len(queries) > 0 {
+		query = "query"
+		s.line("query := url.Values{}")
+		for _, item := range queries {
+			param := s.paramName(item.Name)
+			s.line("if len(", param, ") > 0 {")
+			s.line("query.Set(", strconv.Quote(item.Name), ", ", param, ")")
+			s.line("}")
+		}
+	}
+	path := strings.Join(pathExpr, " + ")
+	if len(path) < 1 {
+		path = `"/"`
+	}
+	call := fmt.Sprintf("%s, %s, %s, %s, %s", strconv.Quote(fun.Method), path, query, strconv.Quote(tokenPlace), argument)
+
+	if raw {
+		s.line("return s.request(", call, ")")
+	} else if output == "" {
+		s.line("return s.do(", call, ", nil)")
+	} else {
+		s.line("var data ", output)
+		s.line("err := s.do(", call, ", &data)")
+		s.line("return data, err")
+	}
+	s.line("}")
+	s.line("")
+}
+
+func (s *golang) writeErrorCodes() {
+	codes := make([]int, 0)
+	for code := range s.errorCodes {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+
+	s.line("type ErrorCode int")
+	s.line("")
+	if len(codes) > 0 {
+		s.line("const (")
+		for _, code := range codes {
+			s.line(s.errorCodeName(code), " ErrorCode = ", code, " // ", s.comment(strings.Join(s.errorCodes[code], "; ")))
+		}
+		s.line(")")
+		s.line("")
+	}
+	s.line("func (s ErrorCode) Summary() string {")
+	s.line("switch s {")
+	for _, code := range codes {
+		s.line("case ", s.errorCodeName(code), ":")
+		s.line("return ", strconv.Quote(strings.Join(s.errorCodes[code], "; ")))
+	}
+	s.line("}")
+	s.line("")
+	s.line(`return ""`)
+	s.line("}")
+	s.line("")
+	s.line("type Error struct {")
+	s.line("Code ErrorCode")
+	s.line("Summary string")
+	s.line("Detail string")
+	s.line("}")
+	s.line("")
+	s.line("func (s *Error) Error() string {")
+	s.line(`return fmt.Sprintf("%d-%s %s", s.Code, s.Summary, s.Detail)`)
+	s.line("}")
+	s.line("")
+}
+
+func (s *golang) writeStructs() {
+	for _, st := range s.structs {
+		s.line("type ", st.name, " struct {")
+		fields := make(map[string]bool)
+		for _, item := range st.model.Children {
+			if item == nil || len(item.Name) < 1 || item.Name == "-" {
+				continue
+			}
+			field := s.exportedName(item.Name)
+			for index := 2; fields[field]; index++ {
+				field = fmt.Sprintf("%s%d", s.exportedName(item.Name), index)
+			}
+			fields[field] = true
+
+			tag := fmt.Sprintf("`json:\"%s\"`", item.Name)
+			if len(item.Note) > 0 {
+				s.line(field, " ", s.fieldType(item.Package, item.Type), " ", tag, " // ", s.comment(item.Note))
+			} else {
+				s.line(field, " ", s.fieldType(item.Package, item.Type), " ", tag)
+			}
+		}
+		s.line("}")
+		s.line("")
+	}
+}
+
+// 接口方法名称: 去除所有接口路径相同的前缀后按路径生成
+func (s *golang) functionNames() []string {
+	paths := make([][]string, 0)
+	for _, fun := range s.functions {
+		segments := make([]string, 0)
+		for _, segment := range strings.Split(fun.Path, "/") {
+			if len(segment) < 1 || strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+				continue
+			}
+			segments = append(segments, segment)
+		}
+		paths = append(paths, segments)
+	}
+
+	prefix := 0
+	if len(paths) > 1 {
+		for {
+			ok := true
+			for _, segments := range paths {
+				if len(segments) <= prefix+1 || segments[prefix] != paths[0][prefix] {
+					ok = false
+					break
+				}
+			}
+			if !ok {
+				break
+			}
+			prefix++
+		}
+	}
+
+	names := make([]string, 0)
+	exists := make(map[string]bool)
+	for index, segments := range paths {
+		name := ""
+		for _, segment := range segments[prefix:] {
+			name += s.identifier(segment)
+		}
+		if len(name) < 1 {
+			name = "Root"
+		}
+		if exists[name] || reservedNames[name] {
+			name += s.exportedName(strings.ToLower(s.functions[index].Method))
+		}
+		base := name
+		for i := 2; exists[name]; i++ {
+			name = fmt.Sprintf("%s%d", base, i)
+		}
+		exists[name] = true
+		names = append(names, name)
+	}
+
+	return names
+}
+
+func (s *golang) inputType(fun *model.Function) string {
+	kind := s.jsonKind(fun.InputSample)
+	if len(fun.InputModel) > 0 {
+		st, ok := s.structMap[s.structKey(fun.InputModel[0].Package, fun.InputModel[0].Name)]
+		if ok {
+			if kind == '[' {
+				return "[]" + st.name
+			} else if kind == '{' {
+				return "*" + st.name
+			}
+		}
+	}
+
+	return "interface{}"
+}
+
+// raw: 非标准结果(types.Result)时直接返回原始数据; 结果数据为空时返回的类型为空
+func (s *golang) outputType(fun *model.Function) (string, bool) {
+	if len(fun.OutputModel) < 1 || s.baseTypeName(fun.OutputModel[0].Name) != "Result" {
+		return "", true
+	}
+
+	for _, item := range fun.OutputModel[0].Children {
+		if item.Name != "data" {
+			continue
+		}
+		if strings.HasPrefix(item.Type, "interface") {
+			return "", false
+		}
+		return s.fieldType(item.Package, item.Type), false
+	}
+
+	return "", false
+}
+
+func (s *golang) fieldType(pkg, name string) string {
+	name = strings.TrimSpace(strings.ReplaceAll(name, "*", ""))
+	if strings.HasSuffix(name, "[]") {
+		elem := s.fieldType(pkg, strings.TrimSuffix(name, "[]"))
+		return "[]" + strings.TrimPrefix(elem, "*")
+	}
+	if strings.HasPrefix(name, "map[string]") {
+		return "map[string]interface{}"
+	}
+	if strings.HasPrefix(name, "[]") || strings.HasPrefix(name, "map[") {
+		return "interface{}"
+	}
+
+	switch name {
+	case "bool", "string",
+		"float32", "float64",
+		"int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64":
+		return name
+	case "DateTime", "types.DateTime":
+		return "types.DateTime"
+	case "Time", "time.Time":
+		s.imports["time"] = true
+		return "time.Time"
+	}
+
+	st, ok := s.structMap[s.structKey(pkg, name)]
+	if ok {
+		return "*" + st.name
+	}
+
+	return "interface{}"
+}
+
+func (s *golang) baseTypeName(name string) string {
+	name = strings.TrimSpace(strings.ReplaceAll(name, "*", ""))
+	index := strings.LastIndex(name, ".")
+	if index >= 0 && !strings.Contains(name, "[") {
+		name = name[index+1:]
+	}
+
+	return name
+}
+
+func (s *golang) errorCodeName(code int) string {
+	return fmt.Sprintf("ErrorCode%d", code)
+}
+
+func (s *golang) exportedName(name string) string {
+	value := s.identifier(name)
+	if len(value) < 1 {
+		return "Field"
+	}
+
+	return value
+}
+
+// 由名称中的ASCII字母及数字生成导出标识符, 没有可用字符时返回空
+func (s *golang) identifier(name string) string {
+	sb := &strings.Builder{}
+	upper := true
+	for _, r := range name {
+		if r > unicode.MaxASCII || (!unicode.IsLetter(r) && !unicode.IsDigit(r)) {
+			upper = true
+			continue
+		}
+		if upper {
+			sb.WriteRune(unicode.ToUpper(r))
+			upper = false
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+
+	value := sb.String()
+	if len(value) > 0 && unicode.IsDigit(rune(value[0])) {
+		value = "N" + value
+	}
+
+	return value
+}
+
+func (s *golang) paramName(name string) string {
+	value := s.exportedName(name)
+	runes := []rune(value)
+	runes[0] = unicode.ToLower(runes[0])
+	value = string(runes)
+
+	switch value {
+	case "argument", "query", "data", "err", "s", "url", "http", "fmt", "client", "types":
+		return value + "Value"
+	}
+	if token.Lookup(value).IsKeyword() {
+		return value + "Value"
+	}
+
+	return value
+}
+
+func (s *golang) comment(v string) string {
+	return strings.Join(strings.Fields(v), " ")
+}
+
+func (s *golang) jsonKind(v interface{}) byte {
+	if v == nil {
+		return 0
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	value := strings.TrimSpace(string(data))
+	if len(value) < 1 {
+		return 0
+	}
+
+	return value[0]
+}
+
+func (s *golang) line(items ...interface{}) {
+	for _, item := range items {
+		s.sb.WriteString(fmt.Sprint(item))
+	}
+	s.sb.WriteString("\n")
+}
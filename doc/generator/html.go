@@ -0,0 +1,101 @@
+package generator
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+const htmlStyle = `body { font-family: -apple-system, "Segoe UI", "Microsoft YaHei", sans-serif; font-size: 14px; color: #333; margin: 0 auto; max-width: 1080px; padding: 16px 32px; }
+h1, h2, h3, h4, h5, h6 { margin: 24px 0 12px; }
+h1 { border-bottom: 2px solid #ddd; padding-bottom: 8px; }
+table { border-collapse: collapse; margin: 8px 0 16px; width: 100%; }
+th, td { border: 1px solid #ddd; padding: 6px 10px; text-align: left; vertical-align: top; }
+th { background: #f5f5f5; }
+pre { background: #f6f8fa; border: 1px solid #eee; padding: 10px; overflow: auto; }
+.toc div { line-height: 1.8; }
+a { color: #0366d6; text-decoration: none; }`
+
+type htmlWriter struct {
+	sb    strings.Builder
+	inToc bool
+}
+
+func (s *htmlWriter) begin(title string) {
+	s.sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	s.sb.WriteString(fmt.Sprintf("<title>%s</title>\n", html.EscapeString(title)))
+	s.sb.WriteString("<style>\n")
+	s.sb.WriteString(htmlStyle)
+	s.sb.WriteString("\n</style>\n</head>\n<body>\n")
+	s.heading(1, "", title)
+}
+
+func (s *htmlWriter) end() {
+	s.endToc()
+	s.sb.WriteString("</body>\n</html>\n")
+}
+
+func (s *htmlWriter) heading(level int, anchor, text string) {
+	s.endToc()
+	if len(anchor) > 0 {
+		s.sb.WriteString(fmt.Sprintf("<h%d id=\"%s\">%s</h%d>\n", level, anchor, html.EscapeString(text), level))
+	} else {
+		s.sb.WriteString(fmt.Sprintf("<h%d>%s</h%d>\n", level, html.EscapeString(text), level))
+	}
+}
+
+func (s *htmlWriter) paragraph(text string) {
+	s.endToc()
+	s.sb.WriteString(fmt.Sprintf("<p>%s</p>\n", s.escape(text)))
+}
+
+func (s *htmlWriter) tocItem(level int, anchor, text string) {
+	if !s.inToc {
+		s.sb.WriteString("<div class=\"toc\">\n")
+		s.inToc = true
+	}
+	s.sb.WriteString(fmt.Sprintf("<div style=\"padding-left: %dem\">", level*2))
+	if len(anchor) > 0 {
+		s.sb.WriteString(fmt.Sprintf("<a href=\"#%s\">%s</a>", anchor, html.EscapeString(text)))
+	} else {
+		s.sb.WriteString(html.EscapeString(text))
+	}
+	s.sb.WriteString("</div>\n")
+}
+
+func (s *htmlWriter) table(headers []string, rows [][]string) {
+	s.endToc()
+	s.sb.WriteString("<table>\n<tr>")
+	for _, header := range headers {
+		s.sb.WriteString(fmt.Sprintf("<th>%s</th>", html.EscapeString(header)))
+	}
+	s.sb.WriteString("</tr>\n")
+	for _, row := range rows {
+		s.sb.WriteString("<tr>")
+		for _, cell := range row {
+			s.sb.WriteString(fmt.Sprintf("<td>%s</td>", s.escape(cell)))
+		}
+		s.sb.WriteString("</tr>\n")
+	}
+	s.sb.WriteString("</table>\n")
+}
+
+func (s *htmlWriter) code(text string) {
+	s.endToc()
+	s.sb.WriteString(fmt.Sprintf("<pre><code>%s</code></pre>\n", html.EscapeString(text)))
+}
+
+func (s *htmlWriter) bytes() []byte {
+	return []byte(s.sb.String())
+}
+
+func (s *htmlWriter) endToc() {
+	if s.inToc {
+		s.sb.WriteString("</div>\n")
+		s.inToc = false
+	}
+}
+
+func (s *htmlWriter) escape(text string) string {
+	return strings.Replace(html.EscapeString(strings.TrimSpace(text)), "\n", "<br>", -1)
+}
@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+type markdownWriter struct {
+	sb     strings.Builder
+	inList bool
+}
+
+func (s *markdownWriter) begin(title string) {
+	s.heading(1, "", title)
+}
+
+func (s *markdownWriter) end() {
+	s.endList()
+}
+
+func (s *markdownWriter) heading(level int, anchor, text string) {
+	s.endList()
+	if len(anchor) > 0 {
+		s.sb.WriteString(fmt.Sprintf("<a id=\"%s\"></a>\n\n", anchor))
+	}
+	s.sb.WriteString(strings.Repeat("#", level))
+	s.sb.WriteString(" ")
+	s.sb.WriteString(s.escape(text))
+	s.sb.WriteString("\n\n")
+}
+
+func (s *markdownWriter) paragraph(text string) {
+	s.endList()
+	s.sb.WriteString(s.escape(text))
+	s.sb.WriteString("\n\n")
+}
+
+func (s *markdownWriter) tocItem(level int, anchor, text string) {
+	s.inList = true
+	s.sb.WriteString(strings.Repeat("  ", level))
+	s.sb.WriteString("- ")
+	if len(anchor) > 0 {
+		s.sb.WriteString(fmt.Sprintf("[%s](#%s)", s.escape(text), anchor))
+	} else {
+		s.sb.WriteString(s.escape(text))
+	}
+	s.sb.WriteString("\n")
+}
+
+func (s *markdownWriter) table(headers []string, rows [][]string) {
+	s.endList()
+	s.row(headers)
+	separators := make([]string, len(headers))
+	for index := range separators {
+		separators[index] = "---"
+	}
+	s.sb.WriteString("| " + strings.Join(separators, " | ") + " |\n")
+	for _, row := range rows {
+		s.row(row)
+	}
+	s.sb.WriteString("\n")
+}
+
+func (s *markdownWriter) row(cells []string) {
+	items := make([]string, 0, len(cells))
+	for _, cell := range cells {
+		items = append(items, strings.Replace(s.escape(cell), "|", "\\|", -1))
+	}
+	s.sb.WriteString("| " + strings.Join(items, " | ") + " |\n")
+}
+
+func (s *markdownWriter) code(text string) {
+	s.endList()
+	s.sb.WriteString("```json\n")
+	s.sb.WriteString(text)
+	s.sb.WriteString("\n```\n\n")
+}
+
+func (s *markdownWriter) bytes() []byte {
+	return []byte(s.sb.String())
+}
+
+// 列表之后须空行
+func (s *markdownWriter) endList() {
+	if s.inList {
+		s.sb.WriteString("\n")
+		s.inList = false
+	}
+}
+
+func (s *markdownWriter) escape(text string) string {
+	return strings.Replace(strings.TrimSpace(text), "\n", "<br>", -1)
+}
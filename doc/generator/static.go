@@ -0,0 +1,262 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/csby/wsf/doc/model"
+	"strings"
+)
+
+// 静态文档的输出格式
+type staticWriter interface {
+	begin(title string)
+	end()
+	heading(level int, anchor, text string)
+	paragraph(text string)
+	// 目录项, level从0开始, anchor为空时不链接
+	tocItem(level int, anchor, text string)
+	table(headers []string, rows [][]string)
+	code(text string)
+	bytes() []byte
+}
+
+func newStatic(writer staticWriter, catalogs model.CatalogSlice, functions map[string]*model.Function) *static {
+	return &static{
+		writer:    writer,
+		catalogs:  catalogs,
+		functions: functions,
+	}
+}
+
+type static struct {
+	writer    staticWriter
+	catalogs  model.CatalogSlice
+	functions map[string]*model.Function
+}
+
+func (s *static) generate(title string) ([]byte, error) {
+	s.writer.begin(title)
+
+	s.writer.heading(2, "", "目录")
+	s.toc(s.catalogs, 0)
+
+	err := s.content(s.catalogs, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	s.writer.end()
+
+	return s.writer.bytes(), nil
+}
+
+func (s *static) toc(catalogs model.CatalogSlice, level int) {
+	for _, catalog := range catalogs {
+		if catalog == nil {
+			continue
+		}
+		if catalog.Type == model.TypeFunction {
+			s.writer.tocItem(level, s.anchor(catalog.ID), catalog.Name)
+			continue
+		}
+		s.writer.tocItem(level, "", catalog.Name)
+		s.toc(catalog.Children, level+1)
+	}
+}
+
+func (s *static) content(catalogs model.CatalogSlice, level int) error {
+	if level > 6 {
+		level = 6
+	}
+
+	for _, catalog := range catalogs {
+		if catalog == nil {
+			continue
+		}
+		if catalog.Type != model.TypeFunction {
+			s.writer.heading(level, "", catalog.Name)
+			err := s.content(catalog.Children, level+1)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		fun, ok := s.functions[catalog.ID]
+		if !ok {
+			continue
+		}
+		err := s.function(fun, level)
+		if err != nil {
+			return fmt.Errorf("function '%s': %v", fun.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *static) function(fun *model.Function, level int) error {
+	s.writer.heading(level, s.anchor(fun.ID), fun.Name)
+	sub := level + 1
+	if sub > 6 {
+		sub = 6
+	}
+
+	rows := [][]string{
+		{"方法", fun.Method},
+		{"地址", fun.Path},
+	}
+	if len(fun.Permission) > 0 {
+		rows = append(rows, []string{"所需角色", fun.Permission})
+	}
+	if len(fun.Since) > 0 {
+		rows = append(rows, []string{"起始版本", fun.Since})
+	}
+	if fun.Deprecation != nil {
+		rows = append(rows, []string{"已弃用", fun.DeprecationText()})
+	}
+	if len(fun.Note) > 0 {
+		rows = append(rows, []string{"说明", fun.Note})
+	}
+	s.writer.table([]string{"项目", "内容"}, rows)
+
+	if len(fun.InputHeaders) > 0 {
+		s.writer.heading(sub, "", "输入头部")
+		s.headers(fun.InputHeaders)
+	}
+	if len(fun.InputQueries) > 0 {
+		s.writer.heading(sub, "", "输入参数")
+		rows = make([][]string, 0)
+		for _, item := range fun.InputQueries {
+			rows = append(rows, []string{item.Name, s.bool(item.Required), item.DefaultValue, strings.Join(item.Values, ", "), item.Note})
+		}
+		s.writer.table([]string{"名称", "必填", "默认值", "可选值", "说明"}, rows)
+	}
+	if len(fun.InputForms) > 0 {
+		s.writer.heading(sub, "", "输入表单")
+		rows = make([][]string, 0)
+		for _, item := range fun.InputForms {
+			kind := "文本"
+			if item.ValueKind == 1 {
+				kind = "文件"
+			}
+			rows = append(rows, []string{item.Key, s.bool(item.Required), kind, item.Note})
+		}
+		s.writer.table([]string{"名称", "必填", "类型", "说明"}, rows)
+	}
+	if len(fun.InputModel) > 0 {
+		s.writer.heading(sub, "", "输入数据")
+		s.models(fun.InputModel)
+	}
+	err := s.examples(sub, "输入示例", fun.InputSample, fun.InputExamples)
+	if err != nil {
+		return err
+	}
+
+	if len(fun.OutputHeaders) > 0 {
+		s.writer.heading(sub, "", "输出头部")
+		s.headers(fun.OutputHeaders)
+	}
+	if len(fun.OutputModel) > 0 {
+		s.writer.heading(sub, "", "输出数据")
+		s.models(fun.OutputModel)
+	}
+	err = s.examples(sub, "输出示例", fun.OutputSample, fun.OutputExamples)
+	if err != nil {
+		return err
+	}
+
+	if len(fun.OutputErrors) > 0 {
+		s.writer.heading(sub, "", "错误代码")
+		rows = make([][]string, 0)
+		for _, item := range fun.OutputErrors {
+			rows = append(rows, []string{fmt.Sprint(item.Code), item.Summary})
+		}
+		s.writer.table([]string{"代码", "描述"}, rows)
+		for _, item := range fun.OutputErrors {
+			if item.Example == nil {
+				continue
+			}
+			s.writer.paragraph(fmt.Sprintf("错误示例: %d-%s", item.Code, item.Summary))
+			err = s.json(item.Example)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *static) headers(items []*model.Header) {
+	rows := make([][]string, 0)
+	for _, item := range items {
+		rows = append(rows, []string{item.Name, s.bool(item.Required), item.DefaultValue, strings.Join(item.Values, ", "), item.Note})
+	}
+	s.writer.table([]string{"名称", "必填", "默认值", "可选值", "说明"}, rows)
+}
+
+func (s *static) models(items []*model.Model) {
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		s.writer.paragraph(item.Name)
+		rows := make([][]string, 0)
+		for _, child := range item.Children {
+			rows = append(rows, []string{child.Name, child.Type, s.bool(child.Required), child.Note, child.Text()})
+		}
+		s.writer.table([]string{"名称", "类型", "必填", "说明", "规则"}, rows)
+	}
+}
+
+func (s *static) examples(level int, title string, sample interface{}, examples model.ExampleSlice) error {
+	if sample != nil {
+		s.writer.heading(level, "", title)
+		err := s.json(sample)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, item := range examples {
+		if item == nil {
+			continue
+		}
+		s.writer.heading(level, "", fmt.Sprintf("%s: %s", title, item.Name))
+		if len(item.Note) > 0 {
+			s.writer.paragraph(item.Note)
+		}
+		if item.IsVariant() {
+			s.writer.paragraph(fmt.Sprintf("%s = %v", item.Discriminator, item.Value))
+		}
+		err := s.json(item.Sample)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *static) json(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		return err
+	}
+	s.writer.code(string(data))
+
+	return nil
+}
+
+func (s *static) anchor(id string) string {
+	return "fn-" + id
+}
+
+func (s *static) bool(v bool) string {
+	if v {
+		return "是"
+	}
+
+	return "否"
+}
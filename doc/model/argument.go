@@ -13,12 +13,16 @@ const (
 )
 
 type Argument struct {
-	parent *Argument
+	parent  *Argument
+	kind    reflect.Kind
+	dynamic bool   // 声明为interface{}, 类型由示例值决定
+	pkg     string // 结构体(或数组元素结构体)所在包的路径
 
 	Name     string `json:"name"`     // 名称
 	Type     string `json:"type"`     // 类型
 	Note     string `json:"note"`     // 说明
 	Required bool   `json:"required"` // 必填
+	Rule
 
 	Children []*Argument `json:"children"`
 }
@@ -103,6 +107,7 @@ func (s *Argument) parseExample(v reflect.Value, argument *Argument) {
 			if argument.Type == "" {
 				argument.Type = t.Name()
 			}
+			argument.pkg = t.PkgPath()
 
 			n := v.NumField()
 			for i := 0; i < n; i++ {
@@ -132,6 +137,8 @@ func (s *Argument) parseExample(v reflect.Value, argument *Argument) {
 						child.Required = true
 					}
 					child.Note = typeField.Tag.Get(tagNote)
+					child.parseTag(child.Name, typeField.Tag)
+					child.dynamic = valueField.Kind() == reflect.Interface
 					child.parent = argument
 					argument.Children = append(argument.Children, child)
 
@@ -164,6 +171,7 @@ func (s *Argument) parseExample(v reflect.Value, argument *Argument) {
 			}
 			if ste != nil {
 				argument.Type = fmt.Sprintf("%s[]", ste.Name())
+				argument.pkg = ste.PkgPath()
 
 				if ste.Kind() == reflect.Struct && argument.ParentType() != ste.Name() {
 					stet := reflect.New(ste)
@@ -189,6 +197,7 @@ func (s *Argument) parseExample(v reflect.Value, argument *Argument) {
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		{
 			argument.Type = t.Name()
+			argument.kind = k
 			break
 		}
 	default:
@@ -203,6 +212,7 @@ func (s *Argument) ToModel() []*Model {
 	results := make([]*Model, 0)
 	model := &Model{
 		Name:     s.typeToKind(s.Type),
+		Package:  s.pkg,
 		Children: make([]*Item, 0),
 	}
 	types := make(map[string]string)
@@ -217,11 +227,13 @@ func (s *Argument) toModel(results *[]*Model, model *Model, children []*Argument
 		return
 	}
 
-	_, ok := types[model.Name]
+	// 不同包中可能存在同名类型
+	key := model.Package + "." + model.Name
+	_, ok := types[key]
 	if ok {
 		return
 	}
-	types[model.Name] = ""
+	types[key] = ""
 	if !strings.Contains(model.Name, "[]") {
 		*results = append(*results, model)
 	}
@@ -238,6 +250,7 @@ func (s *Argument) toModel(results *[]*Model, model *Model, children []*Argument
 		if len(child.Children) > 0 {
 			childModel := &Model{
 				Name:     s.typeToKind(child.Type),
+				Package:  child.pkg,
 				Children: make([]*Item, 0),
 			}
 			s.toModel(results, childModel, child.Children, types)
@@ -256,6 +269,17 @@ func (s *Argument) copyTo(target *Item) {
 
 	target.Name = s.Name
 	target.Type = s.Type
+	target.Package = s.pkg
 	target.Note = s.Note
 	target.Required = s.Required
+	s.copyRule(&target.Rule)
+}
+
+func (s *Argument) fixDynamic() {
+	s.dynamic = false
+	for _, child := range s.Children {
+		if child != nil {
+			child.fixDynamic()
+		}
+	}
 }
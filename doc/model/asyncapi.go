@@ -0,0 +1,127 @@
+package model
+
+import (
+	"fmt"
+)
+
+const (
+	AsyncApiVersion = "2.0.0"
+)
+
+type AsyncApi struct {
+	AsyncApi   string                      `json:"asyncapi"`
+	Info       OpenApiInfo                 `json:"info"`
+	Servers    map[string]*AsyncApiServer  `json:"servers,omitempty"`
+	Channels   map[string]*AsyncApiChannel `json:"channels"`
+	Components *AsyncApiComponents         `json:"components,omitempty"`
+}
+
+type AsyncApiServer struct {
+	Url      string                `json:"url"`
+	Protocol string                `json:"protocol"`
+	Security []map[string][]string `json:"security,omitempty"`
+}
+
+type AsyncApiComponents struct {
+	SecuritySchemes map[string]*OpenApiSecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type AsyncApiChannel struct {
+	Description string                        `json:"description,omitempty"`
+	Parameters  map[string]*AsyncApiParameter `json:"parameters,omitempty"`
+	Subscribe   *AsyncApiOperation            `json:"subscribe,omitempty"`
+	Publish     *AsyncApiOperation            `json:"publish,omitempty"`
+}
+
+type AsyncApiParameter struct {
+	Schema *OpenApiSchema `json:"schema"`
+}
+
+type AsyncApiOperation struct {
+	OperationID string           `json:"operationId"`
+	Summary     string           `json:"summary,omitempty"`
+	Message     *AsyncApiMessage `json:"message"`
+}
+
+type AsyncApiMessage struct {
+	Name        string                   `json:"name,omitempty"`
+	Title       string                   `json:"title,omitempty"`
+	ContentType string                   `json:"contentType,omitempty"`
+	Payload     *OpenApiSchema           `json:"payload,omitempty"`
+	Examples    []map[string]interface{} `json:"examples,omitempty"`
+	OneOf       []*AsyncApiMessage       `json:"oneOf,omitempty"`
+}
+
+// 转换为AsyncAPI的通道定义, 订阅(subscribe)为服务端推送的消息, 发布(publish)为客户端发送的消息
+// 定义了变体时每个变体作为一种消息, 否则使用输入/输出示例
+func (s *Function) ToAsyncApi() *AsyncApiChannel {
+	channel := &AsyncApiChannel{
+		Description: s.Note,
+	}
+	for _, name := range s.pathParameters() {
+		if channel.Parameters == nil {
+			channel.Parameters = make(map[string]*AsyncApiParameter)
+		}
+		channel.Parameters[name] = &AsyncApiParameter{Schema: &OpenApiSchema{Type: "string"}}
+	}
+
+	message := s.OutputExamples.toAsyncApi(s.outputArgument, s.OutputSample)
+	if message != nil {
+		channel.Subscribe = &AsyncApiOperation{
+			OperationID: fmt.Sprintf("%s-subscribe", s.ID),
+			Summary:     s.Name,
+			Message:     message,
+		}
+	}
+	message = s.InputExamples.toAsyncApi(s.inputArgument, s.InputSample)
+	if message != nil {
+		channel.Publish = &AsyncApiOperation{
+			OperationID: fmt.Sprintf("%s-publish", s.ID),
+			Summary:     s.Name,
+			Message:     message,
+		}
+	}
+
+	return channel
+}
+
+func (s ExampleSlice) toAsyncApi(argument *Argument, sample interface{}) *AsyncApiMessage {
+	variants := s.variants()
+	if len(variants) > 0 {
+		message := &AsyncApiMessage{
+			OneOf: make([]*AsyncApiMessage, 0, len(variants)),
+		}
+		for _, item := range variants {
+			message.OneOf = append(message.OneOf, item.toAsyncApi())
+		}
+		return message
+	}
+
+	if sample == nil {
+		return nil
+	}
+	message := &AsyncApiMessage{
+		Examples: []map[string]interface{}{{"payload": sample}},
+	}
+	if argument != nil {
+		message.Payload = argument.ToSchema()
+	}
+
+	return message
+}
+
+func (s *Example) toAsyncApi() *AsyncApiMessage {
+	message := &AsyncApiMessage{
+		Name:     fmt.Sprint(s.Value),
+		Title:    s.Name,
+		Examples: []map[string]interface{}{{"payload": s.Sample}},
+	}
+	if s.argument != nil {
+		message.Payload = s.argument.ToSchema()
+		if property, ok := message.Payload.Properties[s.Discriminator]; ok && property != nil {
+			property.Enum = []interface{}{s.Value}
+		}
+	}
+
+	return message
+}
@@ -7,8 +7,8 @@ import (
 )
 
 const (
-	typeCatalog  = 0
-	typeFunction = 1
+	TypeCatalog  = 0
+	TypeFunction = 1
 )
 
 type Catalog struct {
@@ -34,7 +34,7 @@ func (s *Catalog) AddChild(name string) types.Catalog {
 
 	item := &Catalog{Name: name}
 	item.Children = make(CatalogSlice, 0)
-	item.Type = typeCatalog
+	item.Type = TypeCatalog
 	item.Keywords = name
 	item.index = len(s.Children)
 	item.onAddFunction = s.onAddFunction
@@ -49,7 +49,7 @@ func (s *Catalog) AddFunction(method string, httpPath types.HttpPath, name strin
 	path := httpPath.Path()
 	item := &Catalog{Name: name}
 	item.Children = make(CatalogSlice, 0)
-	item.Type = typeFunction
+	item.Type = TypeFunction
 	item.Keywords = fmt.Sprintf("%s%s", name, path)
 	item.index = len(s.Children)
 
@@ -61,6 +61,7 @@ func (s *Catalog) AddFunction(method string, httpPath types.HttpPath, name strin
 		Path:        path,
 		Name:        name,
 		TokenPlace:  httpPath.TokenPlace(),
+		Permission:  httpPath.Permission(),
 		WebSocket:   httpPath.IsWebSocket(),
 		TokenUI:     httpPath.TokenUI(),
 		TokenCreate: httpPath.TokenCreate(),
@@ -73,12 +74,17 @@ func (s *Catalog) AddFunction(method string, httpPath types.HttpPath, name strin
 	fuc.InputForms = make([]*Form, 0)
 	fuc.OutputHeaders = make([]*Header, 0)
 	fuc.OutputErrors = make(ErrorSlice, 0)
+	fuc.InputExamples = make(ExampleSlice, 0)
+	fuc.OutputExamples = make(ExampleSlice, 0)
 	fuc.SetTokenType(httpPath.TokenType())
-	if method == "POST" {
+	if method == "POST" || method == "PUT" || method == "PATCH" || method == "DELETE" {
 		fuc.SetInputContentType(types.ContentTypeJson)
 		fuc.AddOutputHeader("access-control-allow-origin", "*")
 		fuc.AddOutputHeader(headContentType, "application/json;charset=utf-8")
 	}
+	if len(fuc.Permission) > 0 {
+		fuc.AddOutputErrorCustom(types.ErrForbidden.Code(), fmt.Sprintf("%s(所需角色: %s)", types.ErrForbidden.Summary(), fuc.Permission))
+	}
 	if s.onAddFunction != nil {
 		s.onAddFunction(fuc)
 		item.ID = fuc.ID
@@ -1,8 +1,9 @@
 package model
 
 type Error struct {
-	Code    int    `json:"code" note:"错误代码"`
-	Summary string `json:"summary" note:"错误描述"`
+	Code    int         `json:"code" note:"错误代码"`
+	Summary string      `json:"summary" note:"错误描述"`
+	Example interface{} `json:"example,omitempty" note:"输出示例"`
 }
 
 type ErrorSlice []*Error
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// 数据示例
+// Discriminator为空时表示普通的命名示例, 否则表示由区分字段的值确定数据结构的变体(oneOf)
+type Example struct {
+	Name          string      `json:"name"`          // 示例名称
+	Note          string      `json:"note"`          // 示例说明
+	Discriminator string      `json:"discriminator"` // 区分字段名称, 如: id
+	Value         interface{} `json:"value"`         // 区分字段的值
+	Sample        interface{} `json:"sample"`        // 数据示例
+	Model         []*Model    `json:"model"`         // 数据定义
+
+	argument *Argument
+}
+
+func (s *Example) IsVariant() bool {
+	return len(s.Discriminator) > 0
+}
+
+func newExample(name, note string, v interface{}) *Example {
+	example := &Example{
+		Name:   name,
+		Note:   note,
+		Sample: v,
+	}
+	example.argument = modelArgument.FromExample(v)
+	if example.argument != nil {
+		example.Model = example.argument.ToModel()
+	} else {
+		example.Model = make([]*Model, 0)
+	}
+
+	return example
+}
+
+// 变体的示例即为其完整的数据结构, 示例中声明为interface{}的字段也按示例值的类型验证
+func newVariant(discriminator string, value interface{}, name string, v interface{}) *Example {
+	example := newExample(name, "", v)
+	example.Discriminator = discriminator
+	example.Value = value
+	if example.argument != nil {
+		example.argument.fixDynamic()
+	}
+
+	return example
+}
+
+type ExampleSlice []*Example
+
+// 添加示例, 名称相同时替换
+func (s ExampleSlice) add(example *Example) ExampleSlice {
+	for index, item := range s {
+		if item != nil && item.Name == example.Name {
+			s[index] = example
+			return s
+		}
+	}
+
+	return append(s, example)
+}
+
+func (s ExampleSlice) variants() []*Example {
+	items := make([]*Example, 0)
+	for _, item := range s {
+		if item != nil && item.IsVariant() {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// 根据JSON内容中区分字段的值查找匹配的变体, 未找到时返回nil
+func (s ExampleSlice) match(body []byte) *Example {
+	variants := s.variants()
+	if len(variants) < 1 {
+		return nil
+	}
+
+	values := make(map[string]interface{})
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if decoder.Decode(&values) != nil {
+		return nil
+	}
+	for _, item := range variants {
+		value, ok := values[item.Discriminator]
+		if !ok || value == nil {
+			continue
+		}
+		if fmt.Sprint(value) == fmt.Sprint(item.Value) {
+			return item
+		}
+	}
+
+	return nil
+}
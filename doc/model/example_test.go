@@ -0,0 +1,62 @@
+package model
+
+import (
+	"github.com/csby/wsf/types"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestFunction_Variant(t *testing.T) {
+	fun := &Function{Method: "POST", Path: "/message"}
+	fun.SetInputExample(&types.SocketMessage{ID: 1})
+	fun.AddInputVariant("id", 101, "用户登陆", &types.SocketMessage{ID: 101, Data: &types.OnlineUser{}})
+	fun.AddInputVariant("id", 103, "登陆锁定", &types.SocketMessage{ID: 103, Data: &types.LoginLock{}})
+	fun.AddInputExample("示例", "", &types.SocketMessage{ID: 101, Data: &types.OnlineUser{UserAccount: "admin"}})
+	fun.AddOutputErrorExample(types.ErrInputInvalid, "body.id: 不能为空")
+
+	if len(fun.InputExamples) != 3 {
+		t.Fatal("expect 3 input examples, but got", len(fun.InputExamples))
+	}
+
+	r, _ := http.NewRequest("POST", "/message", nil)
+	errs := fun.ValidateInput(r, []byte(`{"id": 103, "data": {"failures": "x"}}`))
+	if len(errs) != 1 || errs[0].Field != "data.failures" {
+		t.Fatal("expect error of data.failures, but got:", errs.Error())
+	}
+	errs = fun.ValidateInput(r, []byte(`{"id": 1, "data": {"failures": "x"}}`))
+	if len(errs) != 0 {
+		t.Fatal("unexpected errors:", errs.Error())
+	}
+
+	operation := fun.ToOpenApi()
+	media := operation.RequestBody.Content[types.ContentTypeJson]
+	if len(media.Schema.OneOf) != 2 || media.Schema.OneOf[0].Properties["id"].Enum[0] != 101 {
+		t.Fatal("invalid oneOf schema")
+	}
+	if _, ok := media.Examples["示例"]; !ok {
+		t.Fatal("named example not exist")
+	}
+
+	examples := operation.Responses["200"].Content[types.ContentTypeJson].Examples
+	errorExample := examples["12"]
+	if errorExample == nil {
+		t.Fatal("error example not exist")
+	}
+	result, ok := errorExample.Value.(*types.Result)
+	if !ok || !strings.Contains(result.Error.Detail, "不能为空") {
+		t.Fatal("invalid error example:", errorExample.Value)
+	}
+
+
+	// 错误示例不能覆盖同名的命名示例
+	fun.AddOutputExample("101", "", &types.Result{})
+	fun.AddOutputError(types.ErrTokenEmpty)
+	examples = fun.ToOpenApi().Responses["200"].Content[types.ContentTypeJson].Examples
+	if examples["101"] == nil || examples["101"].Summary != "101" {
+		t.Fatal("named example overwritten:", examples["101"])
+	}
+	if examples["error-101"] == nil || examples["error-101"].Summary != types.ErrTokenEmpty.Summary() {
+		t.Fatal("error example not exist")
+	}
+}
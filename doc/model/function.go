@@ -4,6 +4,7 @@ import (
 	"github.com/csby/wsf/types"
 	"sort"
 	"strings"
+	"time"
 )
 
 const (
@@ -23,6 +24,7 @@ type Function struct {
 	FullPath      string      `json:"fullPath"`      // 接口地址
 	TokenType     int         `json:"tokenType"`     // 凭证类型
 	TokenPlace    int         `json:"-"`             // 凭证位置
+	Permission    string      `json:"permission"`    // 所需角色, 为空时不限制
 	WebSocket     bool        `json:"webSocket"`     // 是否为websocket接口
 	InputHeaders  []*Header   `json:"inputHeaders"`  // 输入头部
 	InputQueries  []*Query    `json:"inputQueries"`  // 输入参数
@@ -34,14 +36,66 @@ type Function struct {
 	OutputSample  interface{} `json:"outputSample"`  // 输出数据示例
 	OutputErrors  ErrorSlice  `json:"outputErrors"`  // 输出错误代码
 
+	InputExamples  ExampleSlice `json:"inputExamples"`  // 输入数据的命名示例及变体
+	OutputExamples ExampleSlice `json:"outputExamples"` // 输出数据的命名示例及变体
+
+	Since       string             `json:"since"`       // 起始版本
+	Deprecation *types.Deprecation `json:"deprecation"` // 弃用信息, 为空表示未弃用
+
 	TokenUI     func() []types.TokenUI                                                            `json:"-"`
 	TokenCreate func(items []types.TokenAuth, a types.Assistant) (string, types.ErrorCode, error) `json:"-"`
+
+	inputArgument  *Argument
+	outputArgument *Argument
 }
 
 func (s *Function) SetNote(v string) {
 	s.Note = v
 }
 
+func (s *Function) SetDeprecated(reason, replacement string) {
+	if s.Deprecation == nil {
+		s.Deprecation = &types.Deprecation{}
+	}
+	s.Deprecation.Reason = reason
+	s.Deprecation.Replacement = replacement
+}
+
+func (s *Function) SetSunset(t time.Time) {
+	if s.Deprecation == nil {
+		s.Deprecation = &types.Deprecation{}
+	}
+	sunset := types.DateTime(t)
+	s.Deprecation.Sunset = &sunset
+}
+
+func (s *Function) SetSince(version string) {
+	s.Since = version
+}
+
+// 弃用说明, 如: 原因; 替代接口: /opt.api/v2/login; 停用时间: 2021-01-01 00:00:00, 未弃用时返回空
+func (s *Function) DeprecationText() string {
+	if s.Deprecation == nil {
+		return ""
+	}
+
+	items := make([]string, 0)
+	if len(s.Deprecation.Reason) > 0 {
+		items = append(items, s.Deprecation.Reason)
+	}
+	if len(s.Deprecation.Replacement) > 0 {
+		items = append(items, "替代接口: "+s.Deprecation.Replacement)
+	}
+	if s.Deprecation.Sunset != nil {
+		items = append(items, "停用时间: "+s.Deprecation.Sunset.String())
+	}
+	if len(items) < 1 {
+		return "已弃用"
+	}
+
+	return strings.Join(items, "; ")
+}
+
 func (s *Function) SetTokenType(v int) {
 	if s.TokenType == v {
 		return
@@ -201,6 +255,7 @@ func (s *Function) RemoveInputForm(key string) {
 func (s *Function) SetInputExample(v interface{}) {
 	s.InputSample = v
 	argument := modelArgument.FromExample(v)
+	s.inputArgument = argument
 	if argument != nil {
 		s.InputModel = argument.ToModel()
 	} else {
@@ -208,6 +263,14 @@ func (s *Function) SetInputExample(v interface{}) {
 	}
 }
 
+func (s *Function) AddInputExample(name, note string, v interface{}) {
+	s.InputExamples = s.InputExamples.add(newExample(name, note, v))
+}
+
+func (s *Function) AddInputVariant(discriminator string, value interface{}, name string, v interface{}) {
+	s.InputExamples = s.InputExamples.add(newVariant(discriminator, value, name, v))
+}
+
 func (s *Function) AddOutputHeader(name, value string) {
 	header := s.GetOutputHeader(name)
 	if header != nil {
@@ -245,9 +308,31 @@ func (s *Function) AddOutputErrorCustom(code int, summary string) {
 	sort.Sort(s.OutputErrors)
 }
 
+func (s *Function) AddOutputErrorExample(err types.ErrorCode, detail string) {
+	if err == nil {
+		return
+	}
+	s.AddOutputError(err)
+
+	for _, item := range s.OutputErrors {
+		if item.Code == err.Code() {
+			item.Example = &types.Result{
+				Code:   item.Code,
+				Serial: 201805161315480008,
+				Error: types.ResultError{
+					Summary: item.Summary,
+					Detail:  detail,
+				},
+			}
+			return
+		}
+	}
+}
+
 func (s *Function) SetOutputExample(v interface{}) {
 	s.OutputSample = v
 	argument := modelArgument.FromExample(v)
+	s.outputArgument = argument
 	if argument != nil {
 		s.OutputModel = argument.ToModel()
 	} else {
@@ -266,6 +351,7 @@ func (s *Function) SetOutputDataExample(v interface{}) {
 		Data: v,
 	}
 	argument := modelArgument.FromExample(s.OutputSample)
+	s.outputArgument = argument
 	if argument != nil {
 		s.OutputModel = argument.ToModel()
 	} else {
@@ -274,6 +360,14 @@ func (s *Function) SetOutputDataExample(v interface{}) {
 	s.AddOutputError(types.ErrException)
 }
 
+func (s *Function) AddOutputExample(name, note string, v interface{}) {
+	s.OutputExamples = s.OutputExamples.add(newExample(name, note, v))
+}
+
+func (s *Function) AddOutputVariant(discriminator string, value interface{}, name string, v interface{}) {
+	s.OutputExamples = s.OutputExamples.add(newVariant(discriminator, value, name, v))
+}
+
 func (s *Function) GetInputHeader(name string) *Header {
 	c := len(s.InputHeaders)
 	for i := 0; i < c; i++ {
@@ -2,12 +2,15 @@ package model
 
 type Model struct {
 	Name     string  `json:"name"`
+	Package  string  `json:"package,omitempty"` // 结构体所在包的路径
 	Children []*Item `json:"children"`
 }
 
 type Item struct {
-	Name     string `json:"name"`     // 名称
-	Type     string `json:"type"`     // 类型
-	Note     string `json:"note"`     // 说明
-	Required bool   `json:"required"` // 必填
+	Name     string `json:"name"`              // 名称
+	Type     string `json:"type"`              // 类型
+	Package  string `json:"package,omitempty"` // 类型为结构体时所在包的路径
+	Note     string `json:"note"`              // 说明
+	Required bool   `json:"required"`          // 必填
+	Rule
 }
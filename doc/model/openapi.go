@@ -0,0 +1,525 @@
+package model
+
+import (
+	"fmt"
+	"github.com/csby/wsf/types"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	OpenApiVersion = "3.0.3"
+
+	OpenApiSecurityTokenHeader = "tokenHeader"
+	OpenApiSecurityTokenQuery  = "tokenQuery"
+)
+
+type OpenApi struct {
+	OpenApi    string                                  `json:"openapi"`
+	Info       OpenApiInfo                             `json:"info"`
+	Servers    []*OpenApiServer                        `json:"servers,omitempty"`
+	Tags       []*OpenApiTag                           `json:"tags,omitempty"`
+	Paths      map[string]map[string]*OpenApiOperation `json:"paths"`
+	Components OpenApiComponents                       `json:"components"`
+}
+
+type OpenApiInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type OpenApiServer struct {
+	Url string `json:"url"`
+}
+
+type OpenApiTag struct {
+	Name string `json:"name"`
+}
+
+type OpenApiComponents struct {
+	SecuritySchemes map[string]*OpenApiSecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type OpenApiSecurityScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Description string `json:"description,omitempty"`
+}
+
+type OpenApiOperation struct {
+	Tags        []string                    `json:"tags,omitempty"`
+	Summary     string                      `json:"summary,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	OperationID string                      `json:"operationId"`
+	Parameters  []*OpenApiParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenApiRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenApiResponse `json:"responses"`
+	Security    []map[string][]string       `json:"security,omitempty"`
+	WebSocket   bool                        `json:"x-websocket,omitempty"`
+	Deprecated  bool                        `json:"deprecated,omitempty"`
+	Since       string                      `json:"x-since,omitempty"`
+}
+
+type OpenApiParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required"`
+	Schema      *OpenApiSchema `json:"schema"`
+}
+
+type OpenApiRequestBody struct {
+	Required bool                         `json:"required"`
+	Content  map[string]*OpenApiMediaType `json:"content"`
+}
+
+type OpenApiResponse struct {
+	Description string                       `json:"description"`
+	Headers     map[string]*OpenApiHeader    `json:"headers,omitempty"`
+	Content     map[string]*OpenApiMediaType `json:"content,omitempty"`
+}
+
+type OpenApiHeader struct {
+	Description string         `json:"description,omitempty"`
+	Schema      *OpenApiSchema `json:"schema"`
+}
+
+type OpenApiMediaType struct {
+	Schema   *OpenApiSchema             `json:"schema,omitempty"`
+	Example  interface{}                `json:"example,omitempty"`
+	Examples map[string]*OpenApiExample `json:"examples,omitempty"`
+}
+
+type OpenApiExample struct {
+	Summary string      `json:"summary,omitempty"`
+	Value   interface{} `json:"value"`
+}
+
+type OpenApiSchema struct {
+	Title       string                    `json:"title,omitempty"`
+	Type        string                    `json:"type,omitempty"`
+	Format      string                    `json:"format,omitempty"`
+	Description string                    `json:"description,omitempty"`
+	Items       *OpenApiSchema            `json:"items,omitempty"`
+	Properties  map[string]*OpenApiSchema `json:"properties,omitempty"`
+	Required    []string                  `json:"required,omitempty"`
+	Enum        []interface{}             `json:"enum,omitempty"`
+	Default     interface{}               `json:"default,omitempty"`
+	Minimum     *float64                  `json:"minimum,omitempty"`
+	Maximum     *float64                  `json:"maximum,omitempty"`
+	MinLength   *int                      `json:"minLength,omitempty"`
+	MaxLength   *int                      `json:"maxLength,omitempty"`
+	Pattern     string                    `json:"pattern,omitempty"`
+
+	OneOf []*OpenApiSchema `json:"oneOf,omitempty"`
+}
+
+// 转换为OpenAPI的数据结构定义
+func (s *Argument) ToSchema() *OpenApiSchema {
+	schema := &OpenApiSchema{Description: s.Note}
+
+	if strings.HasSuffix(s.Type, "[]") {
+		schema.Type = "array"
+		if len(s.Children) > 0 && s.Children[0] != nil {
+			schema.Items = s.Children[0].ToSchema()
+		} else {
+			schema.Items = &OpenApiSchema{}
+			schema.Items.setType(strings.TrimSuffix(s.Type, "[]"), reflect.Invalid)
+		}
+		return schema
+	}
+
+	if len(s.Children) > 0 {
+		schema.Type = "object"
+		schema.Properties = make(map[string]*OpenApiSchema)
+		for _, child := range s.Children {
+			if child == nil || len(child.Name) < 1 || child.Name == "-" {
+				continue
+			}
+			schema.Properties[child.Name] = child.ToSchema()
+			if child.Required {
+				schema.Required = append(schema.Required, child.Name)
+			}
+		}
+		return schema
+	}
+
+	schema.setType(s.Type, s.kind)
+	schema.Minimum = s.Min
+	schema.Maximum = s.Max
+	schema.MinLength = s.MinLength
+	schema.MaxLength = s.MaxLength
+	schema.Pattern = s.Pattern
+	schema.Enum = schema.enumValues(s.Enum)
+	if len(s.Format) > 0 {
+		schema.Format = s.Format
+	}
+
+	return schema
+}
+
+// 按数据类型转换可选值, 数字类型无法转换时保留原值
+func (s *OpenApiSchema) enumValues(values []string) []interface{} {
+	if len(values) < 1 {
+		return nil
+	}
+
+	items := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		var item interface{} = value
+		if s.Type == "integer" {
+			if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+				item = v
+			}
+		} else if s.Type == "number" {
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				item = v
+			}
+		}
+		items = append(items, item)
+	}
+
+	return items
+}
+
+func (s *OpenApiSchema) setType(name string, kind reflect.Kind) {
+	if kind == reflect.Invalid {
+		switch name {
+		case "bool":
+			kind = reflect.Bool
+		case "string":
+			kind = reflect.String
+		case "float32", "float64":
+			kind = reflect.Float64
+		case "int", "int8", "int16", "int32", "uint", "uint8", "uint16", "uint32":
+			kind = reflect.Int
+		case "int64", "uint64":
+			kind = reflect.Int64
+		}
+	}
+
+	switch kind {
+	case reflect.Bool:
+		s.Type = "boolean"
+	case reflect.String:
+		s.Type = "string"
+	case reflect.Float32:
+		s.Type = "number"
+		s.Format = "float"
+	case reflect.Float64:
+		s.Type = "number"
+		s.Format = "double"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		s.Type = "integer"
+		s.Format = "int32"
+	case reflect.Int64, reflect.Uint64:
+		s.Type = "integer"
+		s.Format = "int64"
+	default:
+		if name == "DateTime" || name == "Time" {
+			s.Type = "string"
+			s.Format = "date-time"
+		}
+	}
+}
+
+// 转换为OpenAPI的接口定义
+// tags: 接口所在的目录
+func (s *Function) ToOpenApi(tags ...string) *OpenApiOperation {
+	operation := &OpenApiOperation{
+		Tags:        tags,
+		Summary:     s.Name,
+		Description: s.Note,
+		OperationID: s.ID,
+		Parameters:  make([]*OpenApiParameter, 0),
+		Responses:   make(map[string]*OpenApiResponse),
+		WebSocket:   s.WebSocket,
+		Deprecated:  s.Deprecation != nil,
+		Since:       s.Since,
+	}
+	if s.Deprecation != nil {
+		if len(operation.Description) > 0 {
+			operation.Description += "\n\n"
+		}
+		operation.Description += fmt.Sprintf("已弃用: %s", s.DeprecationText())
+	}
+	if len(s.Permission) > 0 {
+		if len(operation.Description) > 0 {
+			operation.Description += "\n\n"
+		}
+		operation.Description += fmt.Sprintf("所需角色: %s", s.Permission)
+	}
+
+	for _, name := range s.pathParameters() {
+		operation.Parameters = append(operation.Parameters, &OpenApiParameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &OpenApiSchema{Type: "string"},
+		})
+	}
+
+	inputContentType := ""
+	for _, header := range s.InputHeaders {
+		if header == nil {
+			continue
+		}
+		if header.Token {
+			operation.Security = append(operation.Security, map[string][]string{OpenApiSecurityTokenHeader: {}})
+			continue
+		}
+		if strings.ToLower(header.Name) == headContentType {
+			inputContentType = header.DefaultValue
+			continue
+		}
+		operation.Parameters = append(operation.Parameters, &OpenApiParameter{
+			Name:        header.Name,
+			In:          "header",
+			Description: header.Note,
+			Required:    header.Required,
+			Schema:      newOpenApiStringSchema(header.DefaultValue, header.Values),
+		})
+	}
+	for _, query := range s.InputQueries {
+		if query == nil {
+			continue
+		}
+		if query.Token {
+			operation.Security = append(operation.Security, map[string][]string{OpenApiSecurityTokenQuery: {}})
+			continue
+		}
+		operation.Parameters = append(operation.Parameters, &OpenApiParameter{
+			Name:        query.Name,
+			In:          "query",
+			Description: query.Note,
+			Required:    query.Required,
+			Schema:      newOpenApiStringSchema(query.DefaultValue, query.Values),
+		})
+	}
+
+	operation.RequestBody = s.openApiRequestBody(inputContentType)
+	operation.Responses["200"] = s.openApiResponse()
+
+	return operation
+}
+
+// 将路由中的参数(:name或*name)转换为OpenAPI的格式({name})
+func (s *Function) OpenApiPath() string {
+	items := strings.Split(s.Path, "/")
+	for index, item := range items {
+		if strings.HasPrefix(item, ":") || strings.HasPrefix(item, "*") {
+			items[index] = fmt.Sprintf("{%s}", item[1:])
+		}
+	}
+
+	return strings.Join(items, "/")
+}
+
+func (s *Function) pathParameters() []string {
+	names := make([]string, 0)
+	items := strings.Split(s.Path, "/")
+	for _, item := range items {
+		if strings.HasPrefix(item, ":") || strings.HasPrefix(item, "*") {
+			names = append(names, item[1:])
+		}
+	}
+
+	return names
+}
+
+func (s *Function) openApiRequestBody(contentType string) *OpenApiRequestBody {
+	if len(s.InputForms) > 0 {
+		schema := &OpenApiSchema{
+			Type:       "object",
+			Properties: make(map[string]*OpenApiSchema),
+		}
+		for _, form := range s.InputForms {
+			if form == nil {
+				continue
+			}
+			property := &OpenApiSchema{
+				Type:        "string",
+				Description: form.Note,
+			}
+			if form.ValueKind == 1 {
+				property.Format = "binary"
+			} else if form.Value != nil {
+				property.Default = form.Value
+			}
+			schema.Properties[form.Key] = property
+			if form.Required {
+				schema.Required = append(schema.Required, form.Key)
+			}
+		}
+
+		return &OpenApiRequestBody{
+			Required: true,
+			Content: map[string]*OpenApiMediaType{
+				"multipart/form-data": {Schema: schema},
+			},
+		}
+	}
+
+	if s.InputSample == nil && len(s.InputExamples) < 1 {
+		return nil
+	}
+	if len(contentType) < 1 {
+		contentType = types.ContentTypeJson
+	}
+	media := &OpenApiMediaType{}
+	if len(s.InputExamples) > 0 {
+		media.Examples = make(map[string]*OpenApiExample)
+		if s.InputSample != nil {
+			media.Examples["default"] = &OpenApiExample{Value: s.InputSample}
+		}
+		s.InputExamples.toOpenApi(media.Examples)
+	} else {
+		media.Example = s.InputSample
+	}
+	if s.inputArgument != nil {
+		media.Schema = s.inputArgument.ToSchema()
+	}
+	if schema := s.InputExamples.toOpenApiSchema(); schema != nil {
+		media.Schema = schema
+	}
+
+	return &OpenApiRequestBody{
+		Required: true,
+		Content: map[string]*OpenApiMediaType{
+			contentType: media,
+		},
+	}
+}
+
+func (s *Function) openApiResponse() *OpenApiResponse {
+	response := &OpenApiResponse{
+		Description: "成功",
+	}
+
+	contentType := ""
+	for _, header := range s.OutputHeaders {
+		if header == nil {
+			continue
+		}
+		if strings.ToLower(header.Name) == headContentType {
+			contentType = strings.TrimSpace(strings.Split(header.DefaultValue, ";")[0])
+			continue
+		}
+		if response.Headers == nil {
+			response.Headers = make(map[string]*OpenApiHeader)
+		}
+		response.Headers[header.Name] = &OpenApiHeader{
+			Description: header.Note,
+			Schema:      newOpenApiStringSchema(header.DefaultValue, header.Values),
+		}
+	}
+
+	if s.OutputSample == nil && len(s.OutputErrors) < 1 && len(s.OutputExamples) < 1 {
+		return response
+	}
+	if len(contentType) < 1 {
+		contentType = types.ContentTypeJson
+	}
+
+	media := &OpenApiMediaType{
+		Examples: make(map[string]*OpenApiExample),
+	}
+	if s.outputArgument != nil {
+		media.Schema = s.outputArgument.ToSchema()
+	}
+	if schema := s.OutputExamples.toOpenApiSchema(); schema != nil {
+		media.Schema = schema
+	}
+	if s.OutputSample != nil {
+		media.Examples["0"] = &OpenApiExample{
+			Summary: "成功",
+			Value:   s.OutputSample,
+		}
+	}
+	s.OutputExamples.toOpenApi(media.Examples)
+	for _, item := range s.OutputErrors {
+		if item == nil {
+			continue
+		}
+		var value interface{} = item.Example
+		if value == nil {
+			value = &types.Result{
+				Code:   item.Code,
+				Serial: 201805161315480008,
+				Error: types.ResultError{
+					Summary: item.Summary,
+				},
+			}
+		}
+		// 错误示例以错误代码命名, 避免覆盖同名的命名示例
+		key := fmt.Sprint(item.Code)
+		if _, ok := media.Examples[key]; ok {
+			key = "error-" + key
+		}
+		media.Examples[key] = &OpenApiExample{
+			Summary: item.Summary,
+			Value:   value,
+		}
+	}
+	response.Content = map[string]*OpenApiMediaType{
+		contentType: media,
+	}
+
+	return response
+}
+
+func newOpenApiStringSchema(defaultValue string, values []string) *OpenApiSchema {
+	schema := &OpenApiSchema{Type: "string"}
+	if len(defaultValue) > 0 {
+		schema.Default = defaultValue
+	}
+	schema.Enum = schema.enumValues(values)
+
+	return schema
+}
+
+func (s ExampleSlice) toOpenApi(examples map[string]*OpenApiExample) {
+	for _, item := range s {
+		if item == nil {
+			continue
+		}
+		summary := item.Note
+		if len(summary) < 1 {
+			summary = item.Name
+		}
+		examples[item.Name] = &OpenApiExample{
+			Summary: summary,
+			Value:   item.Sample,
+		}
+	}
+}
+
+// 由变体生成oneOf结构, 没有变体时返回nil;
+// 变体为内联结构(discriminator仅支持$ref), 由各变体中区分字段的enum值区分
+func (s ExampleSlice) toOpenApiSchema() *OpenApiSchema {
+	variants := s.variants()
+	if len(variants) < 1 {
+		return nil
+	}
+
+	schema := &OpenApiSchema{
+		OneOf: make([]*OpenApiSchema, 0, len(variants)),
+	}
+	for _, item := range variants {
+		variant := &OpenApiSchema{}
+		if item.argument != nil {
+			variant = item.argument.ToSchema()
+		}
+		variant.Title = item.Name
+		if property, ok := variant.Properties[item.Discriminator]; ok && property != nil {
+			property.Enum = []interface{}{item.Value}
+		}
+		schema.OneOf = append(schema.OneOf, variant)
+	}
+
+	return schema
+}
@@ -0,0 +1,222 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	tagMin       = "min"
+	tagMax       = "max"
+	tagMinLength = "minLength"
+	tagMaxLength = "maxLength"
+	tagPattern   = "pattern"
+	tagEnum      = "enum"
+	tagFormat    = "format"
+)
+
+const (
+	FormatEmail    = "email"
+	FormatDate     = "date"
+	FormatDateTime = "date-time"
+	FormatUuid     = "uuid"
+	FormatIP       = "ip"
+)
+
+var (
+	formatEmail = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	formatUuid  = regexp.MustCompile(`^[0-9a-fA-F]{8}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{12}$`)
+)
+
+// 验证规则, 通过结构体标签定义, 如:
+// Age   int    `json:"age" min:"0" max:"150"`
+// Name  string `json:"name" minLength:"1" maxLength:"32" pattern:"^[a-z]+$"`
+// Kind  string `json:"kind" enum:"a,b,c"`
+// Email string `json:"email" format:"email"`
+type Rule struct {
+	Min       *float64 `json:"min,omitempty"`       // 最小值
+	Max       *float64 `json:"max,omitempty"`       // 最大值
+	MinLength *int     `json:"minLength,omitempty"` // 最小长度
+	MaxLength *int     `json:"maxLength,omitempty"` // 最大长度
+	Pattern   string   `json:"pattern,omitempty"`   // 正则表达式
+	Enum      []string `json:"enum,omitempty"`      // 可选值
+	Format    string   `json:"format,omitempty"`    // 格式: email, date, date-time, uuid, ip
+
+	pattern *regexp.Regexp
+}
+
+// 解析字段标签中的验证规则, 标签值无效时panic
+func (s *Rule) parseTag(name string, tag reflect.StructTag) {
+	s.Min = s.parseFloat(name, tagMin, tag.Get(tagMin))
+	s.Max = s.parseFloat(name, tagMax, tag.Get(tagMax))
+	s.MinLength = s.parseInt(name, tagMinLength, tag.Get(tagMinLength))
+	s.MaxLength = s.parseInt(name, tagMaxLength, tag.Get(tagMaxLength))
+
+	s.Pattern = tag.Get(tagPattern)
+	if len(s.Pattern) > 0 {
+		pattern, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			panic(fmt.Sprintf("invalid tag %s:\"%s\" of field '%s': %v", tagPattern, s.Pattern, name, err))
+		}
+		s.pattern = pattern
+	}
+
+	enum := tag.Get(tagEnum)
+	if len(enum) > 0 {
+		s.Enum = make([]string, 0)
+		for _, item := range strings.Split(enum, ",") {
+			s.Enum = append(s.Enum, strings.TrimSpace(item))
+		}
+	}
+
+	s.Format = tag.Get(tagFormat)
+	switch s.Format {
+	case "", FormatEmail, FormatDate, FormatDateTime, FormatUuid, FormatIP:
+	default:
+		panic(fmt.Sprintf("invalid tag %s:\"%s\" of field '%s'", tagFormat, s.Format, name))
+	}
+}
+
+func (s *Rule) copyRule(target *Rule) {
+	if target == nil {
+		return
+	}
+
+	target.Min = s.Min
+	target.Max = s.Max
+	target.MinLength = s.MinLength
+	target.MaxLength = s.MaxLength
+	target.Pattern = s.Pattern
+	target.Enum = s.Enum
+	target.Format = s.Format
+	target.pattern = s.pattern
+}
+
+// 验证值是否符合规则, 返回错误信息, 符合时返回空
+func (s *Rule) validateRule(value interface{}) string {
+	number, isNumber := value.(json.Number)
+	if isNumber {
+		v, err := number.Float64()
+		if err == nil {
+			if s.Min != nil && v < *s.Min {
+				return fmt.Sprintf("不能小于%v", *s.Min)
+			}
+			if s.Max != nil && v > *s.Max {
+				return fmt.Sprintf("不能大于%v", *s.Max)
+			}
+		}
+	}
+
+	text, isString := value.(string)
+	if isString {
+		length := utf8.RuneCountInString(text)
+		if s.MinLength != nil && length < *s.MinLength {
+			return fmt.Sprintf("长度不能小于%d", *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			return fmt.Sprintf("长度不能大于%d", *s.MaxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(text) {
+			return fmt.Sprintf("不匹配格式: %s", s.Pattern)
+		}
+		if len(s.Format) > 0 && !s.matchFormat(text) {
+			return fmt.Sprintf("格式无效, 须为%s", s.Format)
+		}
+	}
+
+	if len(s.Enum) > 0 && (isNumber || isString) {
+		v := text
+		if isNumber {
+			v = number.String()
+		}
+		for _, item := range s.Enum {
+			if item == v {
+				return ""
+			}
+		}
+		return fmt.Sprintf("无效值(%s), 可选值: %s", v, strings.Join(s.Enum, ", "))
+	}
+
+	return ""
+}
+
+func (s *Rule) matchFormat(v string) bool {
+	switch s.Format {
+	case FormatEmail:
+		return formatEmail.MatchString(v)
+	case FormatDate:
+		_, err := time.Parse("2006-01-02", v)
+		return err == nil
+	case FormatDateTime:
+		_, err := time.Parse("2006-01-02 15:04:05", v)
+		if err != nil {
+			_, err = time.Parse(time.RFC3339, v)
+		}
+		return err == nil
+	case FormatUuid:
+		return formatUuid.MatchString(v)
+	case FormatIP:
+		return net.ParseIP(v) != nil
+	}
+
+	return true
+}
+
+func (s *Rule) parseFloat(name, tag, value string) *float64 {
+	if len(value) < 1 {
+		return nil
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		panic(fmt.Sprintf("invalid tag %s:\"%s\" of field '%s': %v", tag, value, name, err))
+	}
+
+	return &v
+}
+
+func (s *Rule) parseInt(name, tag, value string) *int {
+	if len(value) < 1 {
+		return nil
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		panic(fmt.Sprintf("invalid tag %s:\"%s\" of field '%s': %v", tag, value, name, err))
+	}
+
+	return &v
+}
+
+// 规则说明, 如: 最小值: 0; 最大值: 150; 格式: email, 没有规则时返回空
+func (s *Rule) Text() string {
+	items := make([]string, 0)
+	if s.Min != nil {
+		items = append(items, fmt.Sprintf("最小值: %v", *s.Min))
+	}
+	if s.Max != nil {
+		items = append(items, fmt.Sprintf("最大值: %v", *s.Max))
+	}
+	if s.MinLength != nil {
+		items = append(items, fmt.Sprintf("最小长度: %d", *s.MinLength))
+	}
+	if s.MaxLength != nil {
+		items = append(items, fmt.Sprintf("最大长度: %d", *s.MaxLength))
+	}
+	if len(s.Pattern) > 0 {
+		items = append(items, fmt.Sprintf("格式: %s", s.Pattern))
+	}
+	if len(s.Format) > 0 {
+		items = append(items, fmt.Sprintf("格式: %s", s.Format))
+	}
+	if len(s.Enum) > 0 {
+		items = append(items, fmt.Sprintf("可选值: %s", strings.Join(s.Enum, ", ")))
+	}
+
+	return strings.Join(items, "; ")
+}
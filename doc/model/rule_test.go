@@ -0,0 +1,58 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestRule_Validate(t *testing.T) {
+	argument := (&Argument{}).FromExample(&RuleArgument{})
+
+	errs := argument.Validate(nil, []byte(`{"age": 20, "name": "abc", "kind": "a", "email": "a@b.com", "day": "2020-01-02"}`))
+	if len(errs) != 0 {
+		t.Fatal("unexpected errors:", errs.Error())
+	}
+
+	errs = argument.Validate(nil, []byte(`{"age": 200, "name": "A", "kind": "c", "email": "a.com", "day": "20200102"}`))
+	if len(errs) != 5 {
+		t.Fatal("expect 5 errors, but got", len(errs), ":", errs.Error())
+	}
+	fields := []string{"age", "name", "kind", "email", "day"}
+	for index, field := range fields {
+		if errs[index].Field != field {
+			t.Fatal("expect field", field, "but got", errs[index].Field)
+		}
+	}
+
+	schema := argument.ToSchema()
+	age := schema.Properties["age"]
+	if age == nil || age.Minimum == nil || *age.Minimum != 0 || age.Maximum == nil || *age.Maximum != 150 {
+		t.Fatal("invalid schema of age")
+	}
+	name := schema.Properties["name"]
+	if name == nil || name.Pattern != "^[a-z]+$" || name.MaxLength == nil || *name.MaxLength != 8 {
+		t.Fatal("invalid schema of name")
+	}
+	if schema.Properties["email"].Format != FormatEmail {
+		t.Fatal("invalid schema of email")
+	}
+}
+
+func TestRule_ParseTag(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("panic expected for invalid tag")
+		}
+	}()
+
+	(&Argument{}).FromExample(&struct {
+		Age int `json:"age" min:"x"`
+	}{})
+}
+
+type RuleArgument struct {
+	Age   int    `json:"age" min:"0" max:"150"`
+	Name  string `json:"name" minLength:"2" maxLength:"8" pattern:"^[a-z]+$"`
+	Kind  string `json:"kind" enum:"a, b"`
+	Email string `json:"email" format:"email"`
+	Day   string `json:"day" format:"date"`
+}
@@ -0,0 +1,242 @@
+package model
+
+import (
+	"html"
+	"sort"
+	"strings"
+)
+
+const (
+	searchWeightName        = 10
+	searchWeightPath        = 8
+	searchWeightNote        = 4
+	searchWeightArgument    = 3
+	searchWeightCatalog     = 2
+	searchWeightArgumentDoc = 2
+
+	searchBonusExactName  = 20
+	searchBonusPrefixName = 5
+)
+
+type SearchResult struct {
+	ID       string         `json:"id"`       // 接口标识
+	Name     string         `json:"name"`     // 接口名称
+	Method   string         `json:"method"`   // 接口方法
+	Path     string         `json:"path"`     // 接口地址
+	Catalogs []string       `json:"catalogs"` // 所在目录
+	Score    int            `json:"score"`    // 匹配得分, 越高越相关
+	Matches  []*SearchMatch `json:"matches"`  // 匹配的字段
+}
+
+type SearchMatch struct {
+	Field     string `json:"field"`     // 字段, 如: name, path, note, catalog, input.account, input.account.note
+	Text      string `json:"text"`      // 字段内容
+	Highlight string `json:"highlight"` // 高亮后的内容(HTML), 匹配部分以<em></em>标记
+}
+
+type SearchResultSlice []*SearchResult
+
+func (s SearchResultSlice) Len() int {
+	return len(s)
+}
+
+func (s SearchResultSlice) Less(i, j int) bool {
+	if s[i].Score != s[j].Score {
+		return s[i].Score > s[j].Score
+	}
+	if s[i].Name != s[j].Name {
+		return s[i].Name < s[j].Name
+	}
+
+	return s[i].Path < s[j].Path
+}
+
+func (s SearchResultSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// 拆分搜索关键字, 以空白分隔, 不区分大小写
+func SearchTerms(keyword string) []string {
+	terms := make([]string, 0)
+	for _, item := range strings.Fields(strings.ToLower(keyword)) {
+		exist := false
+		for _, term := range terms {
+			if term == item {
+				exist = true
+				break
+			}
+		}
+		if !exist {
+			terms = append(terms, item)
+		}
+	}
+
+	return terms
+}
+
+// 在接口名称、说明、地址、所在目录及参数的名称和说明中搜索, 须匹配所有关键字, 未匹配时返回nil
+func (s *Function) Search(terms []string, catalogs []string) *SearchResult {
+	if len(terms) < 1 {
+		return nil
+	}
+
+	searcher := &functionSearcher{
+		terms:   terms,
+		matched: make(map[string]bool),
+		result: &SearchResult{
+			ID:       s.ID,
+			Name:     s.Name,
+			Method:   s.Method,
+			Path:     s.Path,
+			Catalogs: catalogs,
+			Matches:  make([]*SearchMatch, 0),
+		},
+	}
+
+	searcher.match("name", s.Name, searchWeightName)
+	searcher.match("path", s.Path, searchWeightPath)
+	searcher.match("note", s.Note, searchWeightNote)
+	for _, catalog := range catalogs {
+		searcher.match("catalog", catalog, searchWeightCatalog)
+	}
+	for _, query := range s.InputQueries {
+		if query == nil || query.Token {
+			continue
+		}
+		searcher.argument("query."+query.Name, query.Name, query.Note)
+	}
+	for _, form := range s.InputForms {
+		if form == nil {
+			continue
+		}
+		searcher.argument("form."+form.Key, form.Key, form.Note)
+	}
+	searcher.models("input", s.InputModel)
+	searcher.models("output", s.OutputModel)
+
+	if len(searcher.matched) < len(terms) {
+		return nil
+	}
+
+	name := strings.ToLower(s.Name)
+	for _, term := range terms {
+		if name == term {
+			searcher.result.Score += searchBonusExactName
+		} else if strings.HasPrefix(name, term) {
+			searcher.result.Score += searchBonusPrefixName
+		}
+	}
+
+	return searcher.result
+}
+
+type functionSearcher struct {
+	terms   []string
+	matched map[string]bool
+	result  *SearchResult
+}
+
+func (s *functionSearcher) models(place string, models []*Model) {
+	for _, model := range models {
+		if model == nil {
+			continue
+		}
+		for _, item := range model.Children {
+			if item == nil {
+				continue
+			}
+			s.argument(place+"."+item.Name, item.Name, item.Note)
+		}
+	}
+}
+
+func (s *functionSearcher) argument(field, name, note string) {
+	s.match(field, name, searchWeightArgument)
+	s.match(field+".note", note, searchWeightArgumentDoc)
+}
+
+func (s *functionSearcher) match(field, text string, weight int) {
+	if len(text) < 1 {
+		return
+	}
+
+	lower := strings.ToLower(text)
+	matched := false
+	for _, term := range s.terms {
+		if strings.Contains(lower, term) {
+			s.matched[term] = true
+			s.result.Score += weight
+			matched = true
+		}
+	}
+	if !matched {
+		return
+	}
+
+	// 同一字段(如多个模型中的同名参数)只记录一次
+	for _, item := range s.result.Matches {
+		if item.Field == field && item.Text == text {
+			return
+		}
+	}
+	s.result.Matches = append(s.result.Matches, &SearchMatch{
+		Field:     field,
+		Text:      text,
+		Highlight: highlight(text, s.terms),
+	})
+}
+
+// 以<em></em>标记匹配部分, 其余内容转义为HTML
+func highlight(text string, terms []string) string {
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		// 转换大小写后长度改变, 无法按位置标记
+		return html.EscapeString(text)
+	}
+
+	type interval struct {
+		start, end int
+	}
+	intervals := make([]interval, 0)
+	for _, term := range terms {
+		offset := 0
+		for {
+			index := strings.Index(lower[offset:], term)
+			if index < 0 {
+				break
+			}
+			start := offset + index
+			intervals = append(intervals, interval{start: start, end: start + len(term)})
+			offset = start + len(term)
+		}
+	}
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].start < intervals[j].start
+	})
+
+	// 合并相邻或重叠的部分
+	merged := make([]interval, 0, len(intervals))
+	for _, item := range intervals {
+		count := len(merged)
+		if count > 0 && item.start <= merged[count-1].end {
+			if item.end > merged[count-1].end {
+				merged[count-1].end = item.end
+			}
+			continue
+		}
+		merged = append(merged, item)
+	}
+
+	sb := &strings.Builder{}
+	position := 0
+	for _, item := range merged {
+		sb.WriteString(html.EscapeString(text[position:item.start]))
+		sb.WriteString("<em>")
+		sb.WriteString(html.EscapeString(text[item.start:item.end]))
+		sb.WriteString("</em>")
+		position = item.end
+	}
+	sb.WriteString(html.EscapeString(text[position:]))
+
+	return sb.String()
+}
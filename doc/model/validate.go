@@ -0,0 +1,188 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/csby/wsf/types"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// 根据接口定义验证输入: 头部及参数的必填项和可选值, 以及JSON内容的必填项和数据类型
+// 定义了输入变体时, 按区分字段的值选择对应变体的数据结构进行验证
+func (s *Function) ValidateInput(r *http.Request, body []byte) types.ValidationErrors {
+	errs := make(types.ValidationErrors, 0)
+	if r == nil {
+		return errs
+	}
+
+	for _, item := range s.InputHeaders {
+		if item == nil || item.Token || strings.ToLower(item.Name) == headContentType {
+			continue
+		}
+		errs = s.validateOption(errs, types.ValidationPlaceHeader, item.Name, r.Header.Get(item.Name), item.Required, item.Values)
+	}
+
+	query := r.URL.Query()
+	for _, item := range s.InputQueries {
+		if item == nil || item.Token {
+			continue
+		}
+		errs = s.validateOption(errs, types.ValidationPlaceQuery, item.Name, query.Get(item.Name), item.Required, item.Values)
+	}
+
+	if len(s.InputForms) < 1 {
+		argument := s.inputArgument
+		variant := s.InputExamples.match(body)
+		if variant != nil && variant.argument != nil {
+			argument = variant.argument
+		}
+		if argument != nil {
+			errs = argument.Validate(errs, body)
+		}
+	}
+
+	return errs
+}
+
+func (s *Function) validateOption(errs types.ValidationErrors, place, name, value string, required bool, values []string) types.ValidationErrors {
+	if len(value) < 1 {
+		if required {
+			errs = errs.Add(place, name, "不能为空")
+		}
+		return errs
+	}
+
+	if len(values) < 1 {
+		return errs
+	}
+	for _, item := range values {
+		if item == value {
+			return errs
+		}
+	}
+
+	return errs.Add(place, name, fmt.Sprintf("无效值(%s), 可选值: %s", value, strings.Join(values, ", ")))
+}
+
+// 验证JSON内容
+func (s *Argument) Validate(errs types.ValidationErrors, body []byte) types.ValidationErrors {
+	var value interface{} = nil
+	if len(bytes.TrimSpace(body)) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		err := decoder.Decode(&value)
+		if err != nil {
+			return errs.Add(types.ValidationPlaceBody, "", fmt.Sprintf("JSON格式错误: %v", err))
+		}
+	}
+	if value == nil && len(s.Children) > 0 && !s.isArray() {
+		value = make(map[string]interface{})
+	}
+
+	return s.validateValue(errs, "", value)
+}
+
+func (s *Argument) validateValue(errs types.ValidationErrors, field string, value interface{}) types.ValidationErrors {
+	if s.dynamic || value == nil {
+		return errs
+	}
+
+	if s.isArray() {
+		items, ok := value.([]interface{})
+		if !ok {
+			return errs.Add(types.ValidationPlaceBody, field, "须为数组")
+		}
+		element := &Argument{Type: strings.TrimSuffix(s.Type, "[]")}
+		if len(s.Children) > 0 && s.Children[0] != nil {
+			element = s.Children[0]
+		}
+		for index, item := range items {
+			errs = element.validateValue(errs, fmt.Sprintf("%s[%d]", field, index), item)
+		}
+		return errs
+	}
+
+	if len(s.Children) > 0 {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return errs.Add(types.ValidationPlaceBody, field, "须为对象")
+		}
+		for _, child := range s.Children {
+			if child == nil || len(child.Name) < 1 || child.Name == "-" {
+				continue
+			}
+			name := child.Name
+			if len(field) > 0 {
+				name = field + "." + child.Name
+			}
+			childValue, ok := object[child.Name]
+			if !ok || childValue == nil || childValue == "" {
+				if child.Required {
+					errs = errs.Add(types.ValidationPlaceBody, name, "不能为空")
+					continue
+				}
+				if !ok || childValue == nil {
+					continue
+				}
+			}
+			errs = child.validateValue(errs, name, childValue)
+		}
+		return errs
+	}
+
+	message := s.validateKind(value)
+	if len(message) < 1 {
+		message = s.validateRule(value)
+	}
+	if len(message) > 0 {
+		errs = errs.Add(types.ValidationPlaceBody, field, message)
+	}
+
+	return errs
+}
+
+func (s *Argument) validateKind(value interface{}) string {
+	switch s.schemaType() {
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return "须为布尔值"
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return "须为字符串"
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			return "须为数字"
+		}
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			return "须为整数"
+		}
+		if strings.HasPrefix(s.Type, "uint") || (s.kind >= reflect.Uint && s.kind <= reflect.Uint64) {
+			if _, err := strconv.ParseUint(number.String(), 10, 64); err != nil {
+				return "须为非负整数"
+			}
+		} else if _, err := number.Int64(); err != nil {
+			return "须为整数"
+		}
+	}
+
+	return ""
+}
+
+func (s *Argument) schemaType() string {
+	schema := &OpenApiSchema{}
+	schema.setType(s.Type, s.kind)
+
+	return schema.Type
+}
+
+func (s *Argument) isArray() bool {
+	return strings.HasSuffix(s.Type, "[]")
+}
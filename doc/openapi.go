@@ -0,0 +1,130 @@
+package doc
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/csby/wsf/doc/model"
+	"github.com/csby/wsf/types"
+	"gopkg.in/yaml.v2"
+	"strings"
+)
+
+func (s *doc) OpenApi(info *types.OpenApiInfo, format string) ([]byte, error) {
+	return s.marshal(s.openApi(info), format)
+}
+
+func (s *doc) marshal(document interface{}, format string) ([]byte, error) {
+	data, err := json.MarshalIndent(document, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(format) {
+	case "", types.OpenApiFormatJson:
+		return data, nil
+	case types.OpenApiFormatYaml:
+		// 通过yaml.MapSlice保持字段顺序
+		content := yaml.MapSlice{}
+		err = yaml.Unmarshal(data, &content)
+		if err != nil {
+			return nil, err
+		}
+		return yaml.Marshal(content)
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+func (s *doc) openApi(info *types.OpenApiInfo) *model.OpenApi {
+	document := &model.OpenApi{
+		OpenApi: model.OpenApiVersion,
+		Servers: make([]*model.OpenApiServer, 0),
+		Tags:    make([]*model.OpenApiTag, 0),
+		Paths:   make(map[string]map[string]*model.OpenApiOperation),
+	}
+	if info != nil {
+		document.Info.Title = info.Title
+		document.Info.Description = info.Description
+		document.Info.Version = info.Version
+		for _, server := range info.Servers {
+			document.Servers = append(document.Servers, &model.OpenApiServer{Url: server})
+		}
+	}
+
+	securitySchemes := make(map[string]*model.OpenApiSecurityScheme)
+	s.openApiCatalogs(document, securitySchemes, s.catalogs, nil)
+	if len(securitySchemes) > 0 {
+		document.Components.SecuritySchemes = securitySchemes
+	}
+
+	return document
+}
+
+func (s *doc) openApiCatalogs(document *model.OpenApi, securitySchemes map[string]*model.OpenApiSecurityScheme, catalogs model.CatalogSlice, names []string) {
+	for _, catalog := range catalogs {
+		if catalog == nil {
+			continue
+		}
+
+		if catalog.Type != model.TypeFunction {
+			children := append(append(make([]string, 0, len(names)+1), names...), catalog.Name)
+			s.openApiCatalogs(document, securitySchemes, catalog.Children, children)
+			continue
+		}
+
+		fun, ok := s.functions[catalog.ID]
+		if !ok {
+			continue
+		}
+		tag := strings.Join(names, " / ")
+		operation := fun.ToOpenApi(tag)
+		if len(tag) > 0 {
+			s.addOpenApiTag(document, tag)
+		} else {
+			operation.Tags = nil
+		}
+		for _, security := range operation.Security {
+			for name := range security {
+				securitySchemes[name] = s.openApiSecurityScheme(name)
+			}
+		}
+
+		method := strings.ToLower(fun.Method)
+		if fun.WebSocket {
+			method = "get"
+		}
+		path := fun.OpenApiPath()
+		item, ok := document.Paths[path]
+		if !ok {
+			item = make(map[string]*model.OpenApiOperation)
+			document.Paths[path] = item
+		}
+		if _, ok := item[method]; !ok {
+			item[method] = operation
+		}
+	}
+}
+
+func (s *doc) addOpenApiTag(document *model.OpenApi, name string) {
+	for _, tag := range document.Tags {
+		if tag.Name == name {
+			return
+		}
+	}
+
+	document.Tags = append(document.Tags, &model.OpenApiTag{Name: name})
+}
+
+func (s *doc) openApiSecurityScheme(name string) *model.OpenApiSecurityScheme {
+	scheme := &model.OpenApiSecurityScheme{
+		Type:        "apiKey",
+		Name:        types.TokenName,
+		In:          "header",
+		Description: "凭证",
+	}
+	if name == model.OpenApiSecurityTokenQuery {
+		scheme.In = "query"
+	}
+
+	return scheme
+}
@@ -0,0 +1,72 @@
+package doc
+
+import (
+	"encoding/json"
+	"github.com/csby/wsf/doc/model"
+	"github.com/csby/wsf/types"
+	"strings"
+	"testing"
+)
+
+func TestDoc_OpenApi(t *testing.T) {
+	d := NewDoc(true)
+	path := &types.Path{Prefix: "/api"}
+
+	catalog := d.AddCatalog("管理平台接口").AddChild("权限管理")
+	fun := catalog.AddFunction("POST", path.New("/login"), "用户登录")
+	fun.SetNote("通过用户账号及密码进行登录获取凭证")
+	fun.SetInputExample(&types.LoginFilter{Account: "admin"})
+	fun.SetOutputDataExample(&types.Login{Token: "71b9b7e2ac6d4166b18f414942ff3481"})
+	fun.AddOutputError(types.ErrLoginPasswordInvalid)
+
+	fun = catalog.AddFunction("GET", path.New("/user/:id").SetTokenType(types.TokenTypeAccountPassword), "获取用户")
+	fun.AddInputQuery(false, "detail", "是否返回详细信息", "false", "true", "false")
+
+	data, err := d.OpenApi(&types.OpenApiInfo{Title: "test", Version: "1.0.1"}, types.OpenApiFormatJson)
+	if err != nil {
+		t.Fatal(err)
+	}
+	document := &model.OpenApi{}
+	err = json.Unmarshal(data, document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document.OpenApi != model.OpenApiVersion || document.Info.Title != "test" {
+		t.Fatal("invalid info: ", document.Info)
+	}
+
+	login, ok := document.Paths["/api/login"]["post"]
+	if !ok {
+		t.Fatal("path '/api/login' not exist")
+	}
+	if len(login.Tags) != 1 || login.Tags[0] != "管理平台接口 / 权限管理" {
+		t.Fatal("invalid tags: ", login.Tags)
+	}
+	schema := login.RequestBody.Content[types.ContentTypeJson].Schema
+	if schema.Properties["account"].Description != "账号名称" {
+		t.Fatal("invalid request schema: ", schema.Properties["account"])
+	}
+	examples := login.Responses["200"].Content[types.ContentTypeJson].Examples
+	if _, ok := examples["203"]; !ok {
+		t.Fatal("error example 203 not exist")
+	}
+
+	user, ok := document.Paths["/api/user/{id}"]["get"]
+	if !ok {
+		t.Fatal("path '/api/user/{id}' not exist")
+	}
+	if len(user.Security) != 1 || len(user.Parameters) != 2 || user.Parameters[0].In != "path" {
+		t.Fatal("invalid parameters: ", user.Parameters)
+	}
+	if _, ok := document.Components.SecuritySchemes[model.OpenApiSecurityTokenHeader]; !ok {
+		t.Fatal("security scheme not exist")
+	}
+
+	data, err = d.OpenApi(nil, types.OpenApiFormatYaml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "openapi: 3.0.3") {
+		t.Fatal("invalid yaml: ", string(data))
+	}
+}
@@ -0,0 +1,55 @@
+package doc
+
+import (
+	"github.com/csby/wsf/doc/model"
+	"sort"
+)
+
+const (
+	searchDefaultLimit = 50
+)
+
+func (s *doc) Search(keyword string, limit int) interface{} {
+	results := make(model.SearchResultSlice, 0)
+	terms := model.SearchTerms(keyword)
+	if len(terms) < 1 {
+		return results
+	}
+
+	results = s.searchCatalogs(results, terms, s.catalogs, make([]string, 0))
+	sort.Stable(results)
+
+	if limit < 1 {
+		limit = searchDefaultLimit
+	}
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results
+}
+
+func (s *doc) searchCatalogs(results model.SearchResultSlice, terms []string, catalogs model.CatalogSlice, names []string) model.SearchResultSlice {
+	for _, catalog := range catalogs {
+		if catalog == nil {
+			continue
+		}
+
+		if catalog.Type != model.TypeFunction {
+			children := append(append(make([]string, 0, len(names)+1), names...), catalog.Name)
+			results = s.searchCatalogs(results, terms, catalog.Children, children)
+			continue
+		}
+
+		fun, ok := s.functions[catalog.ID]
+		if !ok {
+			continue
+		}
+		result := fun.Search(terms, names)
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+
+	return results
+}
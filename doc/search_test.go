@@ -0,0 +1,50 @@
+package doc
+
+import (
+	"github.com/csby/wsf/doc/model"
+	"github.com/csby/wsf/types"
+	"testing"
+)
+
+func TestDoc_Search(t *testing.T) {
+	d := NewDoc(true)
+	path := &types.Path{Prefix: "/api"}
+	catalog := d.AddCatalog("管理平台接口").AddChild("用户")
+	login := catalog.AddFunction("POST", path.New("/login"), "用户登录")
+	login.SetNote("使用账号和密码登录")
+	login.AddInputQuery(false, "account", "登录账号", "")
+	catalog.AddFunction("POST", path.New("/logout"), "用户注销")
+	catalog.AddFunction("POST", path.New("/user/list"), "获取用户列表")
+
+	results, ok := d.Search("LOGIN", 0).(model.SearchResultSlice)
+	if !ok {
+		t.Fatal("invalid result type")
+	}
+	if len(results) != 1 || results[0].Path != "/api/login" {
+		t.Fatal("expect /api/login, but got", results)
+	}
+	if len(results[0].Catalogs) != 2 || results[0].Catalogs[1] != "用户" {
+		t.Fatal("invalid catalogs:", results[0].Catalogs)
+	}
+	if results[0].Matches[0].Field != "path" || results[0].Matches[0].Highlight != "/api/<em>login</em>" {
+		t.Fatal("invalid highlight:", results[0].Matches[0].Highlight)
+	}
+
+	results = d.Search("用户 账号", 0).(model.SearchResultSlice)
+	if len(results) != 1 || results[0].Name != "用户登录" {
+		t.Fatal("expect all terms matched, but got", len(results))
+	}
+
+	results = d.Search("用户", 2).(model.SearchResultSlice)
+	if len(results) != 2 {
+		t.Fatal("expect 2 results, but got", len(results))
+	}
+	if results[0].Name != "用户注销" && results[0].Name != "用户登录" {
+		t.Fatal("expect name prefix ranked first, but got", results[0].Name)
+	}
+
+	results = d.Search(" ", 0).(model.SearchResultSlice)
+	if len(results) != 0 {
+		t.Fatal("expect empty, but got", len(results))
+	}
+}
@@ -1,13 +1,18 @@
 package web
 
 import (
+	"fmt"
 	"github.com/csby/wsf/types"
 	"net/http"
+	"strings"
 )
 
 type controller struct {
-	doc  types.Doc
-	info types.ServerInformation
+	doc    types.Doc
+	info   types.ServerInformation
+	store  *tryStore
+	client *http.Client
+	urls   map[string]string
 }
 
 func (s *controller) GetInformation(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
@@ -23,6 +28,26 @@ func (s *controller) GetCatalogTree(w http.ResponseWriter, r *http.Request, p ty
 	a.Success(s.doc.Catalogs())
 }
 
+func (s *controller) Search(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+	if s.doc == nil {
+		a.Error(types.ErrInternal, "doc is nil")
+		return
+	}
+
+	filter := &types.DocSearchFilter{}
+	err := a.GetJson(filter)
+	if err != nil {
+		a.Error(types.ErrInput, err)
+		return
+	}
+	if len(strings.TrimSpace(filter.Keyword)) < 1 {
+		a.Error(types.ErrInput, "keyword is empty")
+		return
+	}
+
+	a.Success(s.doc.Search(filter.Keyword, filter.Limit))
+}
+
 func (s *controller) GetFunctionDetail(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
 	if s.doc == nil {
 		a.Error(types.ErrInternal, "doc is nil")
@@ -77,3 +102,43 @@ func (s *controller) CreateToken(w http.ResponseWriter, r *http.Request, p types
 
 	a.Success(token)
 }
+
+func (s *controller) GetOpenApi(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+	if s.doc == nil {
+		a.Error(types.ErrInternal, "doc is nil")
+		return
+	}
+
+	s.writeApiDocument(w, r, a, s.doc.OpenApi)
+}
+
+func (s *controller) GetAsyncApi(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+	if s.doc == nil {
+		a.Error(types.ErrInternal, "doc is nil")
+		return
+	}
+
+	s.writeApiDocument(w, r, a, s.doc.AsyncApi)
+}
+
+func (s *controller) writeApiDocument(w http.ResponseWriter, r *http.Request, a types.Assistant, export func(info *types.OpenApiInfo, format string) ([]byte, error)) {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	info := &types.OpenApiInfo{
+		Title:   s.info.Name,
+		Version: s.info.Version,
+		Servers: []string{fmt.Sprintf("%s://%s", a.Schema(), r.Host)},
+	}
+	data, err := export(info, format)
+	if err != nil {
+		a.Error(types.ErrInput, err)
+		return
+	}
+
+	contentType := types.ContentTypeJson
+	if format == types.OpenApiFormatYaml {
+		contentType = types.ContentTypeYaml
+	}
+	w.Header().Add("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", contentType+";charset=utf-8")
+	w.Write(data)
+}
@@ -16,6 +16,17 @@ const (
 	ApiPathFunctionDetail = "/function/:id"
 	ApiPathTokenUI        = "/token/ui/:id"
 	ApiPathTokenCreate    = "/token/create/:id"
+	ApiPathOpenApi        = "/openapi"
+	ApiPathAsyncApi       = "/asyncapi"
+	ApiPathSearch         = "/search"
+
+	ApiPathTryRun          = "/try/run/:id"
+	ApiPathTryCurl         = "/try/curl/:id"
+	ApiPathTryHistory      = "/try/history/list"
+	ApiPathTryHistoryClear = "/try/history/clear"
+	ApiPathTrySaved        = "/try/saved/list"
+	ApiPathTrySave         = "/try/saved/save"
+	ApiPathTrySavedDelete  = "/try/saved/delete"
 )
 
 // rootPath: site path in location
@@ -31,12 +42,39 @@ func NewHandler(rootPath, sitePrefix, apiPrefix string) Handler {
 
 type Handler interface {
 	Init(router types.Router, info *types.ServerInformation)
+	// 设置调试记录(历史记录及保存的请求)的保存文件夹, 每个账号一个文件, 为空时仅保存在内存中
+	SetStore(folder string) error
+	// 启用调试接口, 在Init之前调用
+	// urls: 按协议(http或https)设置的请求地址, 如: {"https": "https://127.0.0.1:8443"}
+	// check: 凭证验证, 验证通过后须设置types.RouterKeyAccount, 为nil时不提供调试接口
+	// insecure: 是否跳过服务端证书验证
+	SetTry(urls map[string]string, check types.RouterPreHandle, insecure bool)
 }
 
 type handler struct {
-	rootPath   string
-	sitePrefix string
-	apiPrefix  string
+	rootPath    string
+	sitePrefix  string
+	apiPrefix   string
+	store       *tryStore
+	tryUrls     map[string]string
+	tryCheck    types.RouterPreHandle
+	tryInsecure bool
+}
+
+func (s *handler) SetTry(urls map[string]string, check types.RouterPreHandle, insecure bool) {
+	s.tryUrls = urls
+	s.tryCheck = check
+	s.tryInsecure = insecure
+}
+
+func (s *handler) SetStore(folder string) error {
+	store, err := newTryStore(folder)
+	if err != nil {
+		return err
+	}
+	s.store = store
+
+	return nil
 }
 
 func (s *handler) Init(router types.Router, info *types.ServerInformation) {
@@ -46,7 +84,15 @@ func (s *handler) Init(router types.Router, info *types.ServerInformation) {
 
 	// api
 	apiPath := types.Path{Prefix: s.apiPrefix}
-	ctrl := &controller{doc: router.Document()}
+	if s.store == nil {
+		s.store, _ = newTryStore("")
+	}
+	ctrl := &controller{
+		doc:    router.Document(),
+		store:  s.store,
+		client: newTryClient(s.tryInsecure),
+		urls:   s.tryUrls,
+	}
 	if info != nil {
 		ctrl.info.Name = info.Name
 		ctrl.info.Version = info.Version
@@ -58,6 +104,9 @@ func (s *handler) Init(router types.Router, info *types.ServerInformation) {
 	// 获取接口目录信息
 	router.POST(apiPath.New(ApiPathCatalogTree), nil, ctrl.GetCatalogTree, nil)
 
+	// 搜索接口
+	router.POST(apiPath.New(ApiPathSearch), nil, ctrl.Search, nil)
+
 	// 获取接口定义信息
 	router.POST(apiPath.New(ApiPathFunctionDetail), nil, ctrl.GetFunctionDetail, nil)
 
@@ -66,4 +115,35 @@ func (s *handler) Init(router types.Router, info *types.ServerInformation) {
 
 	// 创建凭证
 	router.POST(apiPath.New(ApiPathTokenCreate), nil, ctrl.CreateToken, nil)
+
+	// 导出OpenAPI文档(?format=json|yaml)
+	router.GET(apiPath.New(ApiPathOpenApi), nil, ctrl.GetOpenApi, nil)
+
+	// 导出websocket接口的AsyncAPI文档(?format=json|yaml)
+	router.GET(apiPath.New(ApiPathAsyncApi), nil, ctrl.GetAsyncApi, nil)
+
+	if s.tryCheck == nil {
+		return
+	}
+
+	// 调试: 在当前主机上执行接口并记录历史
+	router.POST(apiPath.New(ApiPathTryRun), s.tryCheck, ctrl.Try, nil)
+
+	// 调试: 导出为curl命令
+	router.POST(apiPath.New(ApiPathTryCurl), s.tryCheck, ctrl.TryCurl, nil)
+
+	// 调试: 获取历史记录
+	router.POST(apiPath.New(ApiPathTryHistory), s.tryCheck, ctrl.GetTryHistory, nil)
+
+	// 调试: 清空历史记录
+	router.POST(apiPath.New(ApiPathTryHistoryClear), s.tryCheck, ctrl.ClearTryHistory, nil)
+
+	// 调试: 获取保存的请求
+	router.POST(apiPath.New(ApiPathTrySaved), s.tryCheck, ctrl.GetTrySaved, nil)
+
+	// 调试: 保存请求
+	router.POST(apiPath.New(ApiPathTrySave), s.tryCheck, ctrl.SaveTry, nil)
+
+	// 调试: 删除保存的请求
+	router.POST(apiPath.New(ApiPathTrySavedDelete), s.tryCheck, ctrl.DeleteTrySaved, nil)
 }
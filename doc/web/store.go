@@ -0,0 +1,199 @@
+package web
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/csby/wsf/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	tryHistoryMaxCount = 100
+	// 单个账号的调试记录最大长度, 超过时删除最早的历史记录
+	tryUserMaxSize = 4 * 1024 * 1024
+)
+
+type tryUser struct {
+	History []*types.DocTryRecord `json:"history"`
+	Saved   []*types.DocTryRecord `json:"saved"`
+}
+
+// 调试记录, 每个账号保存为文件夹中的一个文件, folder为空时仅保存在内存中
+type tryStore struct {
+	sync.Mutex
+
+	folder string
+	users  map[string]*tryUser
+}
+
+func newTryStore(folder string) (*tryStore, error) {
+	instance := &tryStore{
+		folder: folder,
+		users:  make(map[string]*tryUser),
+	}
+
+	if len(folder) > 0 {
+		err := os.MkdirAll(folder, 0700)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return instance, nil
+}
+
+func (s *tryStore) AddHistory(user string, record *types.DocTryRecord) error {
+	s.Lock()
+	defer s.Unlock()
+
+	item := s.user(user)
+	history := append([]*types.DocTryRecord{record}, item.History...)
+	if len(history) > tryHistoryMaxCount {
+		history = history[:tryHistoryMaxCount]
+	}
+	item.History = history
+
+	return s.save(user, item)
+}
+
+func (s *tryStore) History(user, functionId string) []*types.DocTryRecord {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.filter(s.user(user).History, functionId)
+}
+
+func (s *tryStore) ClearHistory(user, functionId string) (int, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	item := s.user(user)
+	history := make([]*types.DocTryRecord, 0)
+	for _, record := range item.History {
+		if len(functionId) > 0 && record.FunctionID != functionId {
+			history = append(history, record)
+		}
+	}
+	count := len(item.History) - len(history)
+	item.History = history
+	if count < 1 {
+		return 0, nil
+	}
+
+	return count, s.save(user, item)
+}
+
+// 保存请求, 同一接口下名称相同时替换
+func (s *tryStore) Save(user string, record *types.DocTryRecord) error {
+	s.Lock()
+	defer s.Unlock()
+
+	item := s.user(user)
+	for index, saved := range item.Saved {
+		if saved.FunctionID == record.FunctionID && saved.Name == record.Name {
+			record.ID = saved.ID
+			item.Saved[index] = record
+			return s.save(user, item)
+		}
+	}
+	item.Saved = append(item.Saved, record)
+
+	return s.save(user, item)
+}
+
+func (s *tryStore) Saved(user, functionId string) []*types.DocTryRecord {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.filter(s.user(user).Saved, functionId)
+}
+
+func (s *tryStore) DeleteSaved(user, id string) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	item := s.user(user)
+	for index, saved := range item.Saved {
+		if saved.ID == id {
+			item.Saved = append(item.Saved[:index], item.Saved[index+1:]...)
+			return true, s.save(user, item)
+		}
+	}
+
+	return false, nil
+}
+
+// 获取账号的调试记录, 不在内存中时从文件加载
+func (s *tryStore) user(name string) *tryUser {
+	item, ok := s.users[name]
+	if ok {
+		return item
+	}
+
+	item = &tryUser{}
+	if len(s.folder) > 0 {
+		data, err := ioutil.ReadFile(s.filePath(name))
+		if err == nil {
+			json.Unmarshal(data, item)
+		}
+	}
+	if item.History == nil {
+		item.History = make([]*types.DocTryRecord, 0)
+	}
+	if item.Saved == nil {
+		item.Saved = make([]*types.DocTryRecord, 0)
+	}
+	s.users[name] = item
+
+	return item
+}
+
+func (s *tryStore) filter(records []*types.DocTryRecord, functionId string) []*types.DocTryRecord {
+	items := make([]*types.DocTryRecord, 0)
+	for _, record := range records {
+		if len(functionId) > 0 && record.FunctionID != functionId {
+			continue
+		}
+		items = append(items, record)
+	}
+
+	return items
+}
+
+func (s *tryStore) save(name string, item *tryUser) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	for len(data) > tryUserMaxSize && len(item.History) > 0 {
+		item.History = item.History[:len(item.History)-1]
+		data, err = json.Marshal(item)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(s.folder) < 1 {
+		return nil
+	}
+
+	filePath := s.filePath(name)
+	tempPath := filePath + ".tmp"
+	err = ioutil.WriteFile(tempPath, data, 0600)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tempPath, filePath)
+}
+
+// 账号名称可能包含路径字符, 使用摘要作为文件名
+func (s *tryStore) filePath(name string) string {
+	hash := sha1.Sum([]byte(name))
+
+	return filepath.Join(s.folder, hex.EncodeToString(hash[:])+".json")
+}
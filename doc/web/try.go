@@ -0,0 +1,411 @@
+package web
+
+import (
+	"crypto/tls"
+	"fmt"
+	"github.com/csby/wsf/doc/model"
+	"github.com/csby/wsf/types"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	tryBodyMaxSize = 1024 * 1024
+)
+
+// insecure: 是否跳过服务端证书验证
+func newTryClient(insecure bool) *http.Client {
+	return &http.Client{
+		Timeout: 60 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure},
+		},
+	}
+}
+
+func (s *controller) Try(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+	user, ok := s.getTryUser(a)
+	if !ok {
+		return
+	}
+	argument := &types.DocTryArgument{}
+	err := a.GetJson(argument)
+	if err != nil {
+		a.Error(types.ErrInput, err)
+		return
+	}
+
+	record, code, err := s.newTryRecord(p.ByName("id"), &argument.Input, a, r)
+	if code != nil {
+		a.Error(code, err)
+		return
+	}
+	record.ID = a.NewGuid()
+	record.Output = s.tryExecute(record)
+	sanitizeTryRecord(record)
+
+	err = s.store.AddHistory(user, record)
+	if err != nil {
+		a.Error(types.ErrInternal, err)
+		return
+	}
+
+	a.Success(record)
+}
+
+func (s *controller) TryCurl(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+	argument := &types.DocTryArgument{}
+	err := a.GetJson(argument)
+	if err != nil {
+		a.Error(types.ErrInput, err)
+		return
+	}
+
+	record, code, err := s.newTryRecord(p.ByName("id"), &argument.Input, a, r)
+	if code != nil {
+		a.Error(code, err)
+		return
+	}
+
+	a.Success(toCurl(record))
+}
+
+func (s *controller) GetTryHistory(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+	filter := &types.DocTryFilter{}
+	user, ok := s.getTryFilter(a, filter)
+	if !ok {
+		return
+	}
+
+	a.Success(s.store.History(user, filter.FunctionID))
+}
+
+func (s *controller) ClearTryHistory(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+	filter := &types.DocTryFilter{}
+	user, ok := s.getTryFilter(a, filter)
+	if !ok {
+		return
+	}
+
+	count, err := s.store.ClearHistory(user, filter.FunctionID)
+	if err != nil {
+		a.Error(types.ErrInternal, err)
+		return
+	}
+
+	a.Success(count)
+}
+
+func (s *controller) GetTrySaved(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+	filter := &types.DocTryFilter{}
+	user, ok := s.getTryFilter(a, filter)
+	if !ok {
+		return
+	}
+
+	a.Success(s.store.Saved(user, filter.FunctionID))
+}
+
+func (s *controller) SaveTry(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+	argument := &types.DocTrySave{}
+	user, ok := s.getTryFilter(a, argument)
+	if !ok {
+		return
+	}
+	if len(argument.Name) < 1 {
+		a.Error(types.ErrInput, "name is empty")
+		return
+	}
+
+	record, code, err := s.newTryRecord(argument.FunctionID, &argument.Input, a, r)
+	if code != nil {
+		a.Error(code, err)
+		return
+	}
+	record.ID = a.NewGuid()
+	record.Name = argument.Name
+	sanitizeTryRecord(record)
+
+	err = s.store.Save(user, record)
+	if err != nil {
+		a.Error(types.ErrInternal, err)
+		return
+	}
+
+	a.Success(record)
+}
+
+func (s *controller) DeleteTrySaved(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+	argument := &types.DocTryDelete{}
+	user, ok := s.getTryFilter(a, argument)
+	if !ok {
+		return
+	}
+
+	ok, err := s.store.DeleteSaved(user, argument.ID)
+	if err != nil {
+		a.Error(types.ErrInternal, err)
+		return
+	}
+	if !ok {
+		a.Error(types.ErrNotExist, fmt.Sprintf("id '%s' not exist", argument.ID))
+		return
+	}
+
+	a.Success(argument.ID)
+}
+
+func (s *controller) getTryFilter(a types.Assistant, argument interface{}) (string, bool) {
+	user, ok := s.getTryUser(a)
+	if !ok {
+		return "", false
+	}
+	err := a.GetJson(argument)
+	if err != nil {
+		a.Error(types.ErrInput, err)
+		return "", false
+	}
+
+	return user, true
+}
+
+// 调试记录按凭证验证后的账号保存
+func (s *controller) getTryUser(a types.Assistant) (string, bool) {
+	v, ok := a.Get(types.RouterKeyAccount)
+	if ok {
+		user := fmt.Sprint(v)
+		if len(user) > 0 {
+			return user, true
+		}
+	}
+
+	a.Error(types.ErrTokenInvalid, "account of token is empty")
+	return "", false
+}
+
+func (s *controller) newTryRecord(id string, input *types.DocTryInput, a types.Assistant, r *http.Request) (*types.DocTryRecord, types.ErrorCode, error) {
+	if s.doc == nil {
+		return nil, types.ErrInternal, fmt.Errorf("doc is nil")
+	}
+	base := s.tryBase(a.Schema())
+	if len(base) < 1 {
+		return nil, types.ErrNotSupport, fmt.Errorf("no listener for try")
+	}
+
+	// r.Host仅用于文档显示的完整路径, 请求地址由配置的监听地址生成
+	v, err := s.doc.Function(id, a.Schema(), r.Host)
+	if err != nil {
+		return nil, types.ErrInput, err
+	}
+	fun, ok := v.(*model.Function)
+	if !ok {
+		return nil, types.ErrInternal, fmt.Errorf("invalid function type: %T", v)
+	}
+	if fun.WebSocket || len(fun.InputForms) > 0 {
+		return nil, types.ErrNotSupport, fmt.Errorf("websocket or form function is not supported")
+	}
+
+	record := &types.DocTryRecord{
+		FunctionID: fun.ID,
+		Method:     fun.Method,
+		Path:       fun.Path,
+		Time:       types.DateTime(time.Now()),
+		Input:      *input,
+	}
+	if record.Input.Headers == nil {
+		record.Input.Headers = make(map[string]string)
+	}
+	contentType := fun.GetInputHeader("content-type")
+	if contentType != nil && len(record.Input.Body) > 0 && !hasHeader(record.Input.Headers, "content-type") {
+		record.Input.Headers[contentType.Name] = contentType.DefaultValue
+	}
+	record.Url, err = tryUrl(fun.Path, input, base)
+	if err != nil {
+		return nil, types.ErrInput, err
+	}
+
+	return record, nil, nil
+}
+
+// 调试请求只发送到本服务配置的监听地址, 优先使用与当前请求相同的协议
+func (s *controller) tryBase(schema string) string {
+	base, ok := s.urls[schema]
+	if ok {
+		return base
+	}
+	for _, name := range []string{"https", "http"} {
+		base, ok = s.urls[name]
+		if ok {
+			return base
+		}
+	}
+
+	return ""
+}
+
+func (s *controller) tryExecute(record *types.DocTryRecord) *types.DocTryOutput {
+	output := &types.DocTryOutput{
+		Headers: make(map[string]string),
+	}
+
+	var body io.Reader = nil
+	if len(record.Input.Body) > 0 {
+		body = strings.NewReader(record.Input.Body)
+	}
+	req, err := http.NewRequest(record.Method, record.Url, body)
+	if err != nil {
+		output.Error = err.Error()
+		return output
+	}
+	for name, value := range record.Input.Headers {
+		req.Header.Set(name, value)
+	}
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	if err != nil {
+		output.Elapsed = int64(time.Since(start) / time.Millisecond)
+		output.Error = err.Error()
+		return output
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, tryBodyMaxSize))
+	output.Elapsed = int64(time.Since(start) / time.Millisecond)
+	if err != nil {
+		output.Error = err.Error()
+	}
+	output.Status = resp.StatusCode
+	output.Body = string(data)
+	for name, values := range resp.Header {
+		output.Headers[name] = strings.Join(values, ", ")
+	}
+
+	return output
+}
+
+// 替换路径参数(:name或*name)并添加请求参数, base如: https://127.0.0.1:8443
+func tryUrl(path string, input *types.DocTryInput, base string) (string, error) {
+	items := strings.Split(path, "/")
+	for index, item := range items {
+		if strings.HasPrefix(item, ":") {
+			value := input.Params[item[1:]]
+			if len(value) < 1 {
+				return "", fmt.Errorf("path parameter '%s' is empty", item[1:])
+			}
+			items[index] = url.PathEscape(value)
+		} else if strings.HasPrefix(item, "*") {
+			segments := strings.Split(strings.TrimPrefix(input.Params[item[1:]], "/"), "/")
+			for i, segment := range segments {
+				segments[i] = url.PathEscape(segment)
+			}
+			items[index] = strings.Join(segments, "/")
+		}
+	}
+
+	sb := &strings.Builder{}
+	sb.WriteString(base)
+	sb.WriteString(strings.Join(items, "/"))
+	if len(input.Queries) > 0 {
+		values := url.Values{}
+		for name, value := range input.Queries {
+			values.Set(name, value)
+		}
+		sb.WriteString("?")
+		sb.WriteString(values.Encode())
+	}
+
+	return sb.String(), nil
+}
+
+func toCurl(record *types.DocTryRecord) string {
+	sb := &strings.Builder{}
+	sb.WriteString("curl -X ")
+	sb.WriteString(record.Method)
+	sb.WriteString(" ")
+	sb.WriteString(quoteShell(record.Url))
+
+	names := make([]string, 0, len(record.Input.Headers))
+	for name := range record.Input.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sb.WriteString(" -H ")
+		sb.WriteString(quoteShell(fmt.Sprintf("%s: %s", name, record.Input.Headers[name])))
+	}
+
+	if len(record.Input.Body) > 0 {
+		sb.WriteString(" --data-raw ")
+		sb.WriteString(quoteShell(record.Input.Body))
+	}
+
+	return sb.String()
+}
+
+func quoteShell(v string) string {
+	return "'" + strings.Replace(v, "'", `'\''`, -1) + "'"
+}
+
+func hasHeader(headers map[string]string, name string) bool {
+	for key := range headers {
+		if strings.ToLower(key) == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// 删除凭证等敏感信息, 调试记录中不保存
+func sanitizeTryRecord(record *types.DocTryRecord) {
+	headers := make(map[string]string)
+	for name, value := range record.Input.Headers {
+		if !isSecretName(name) {
+			headers[name] = value
+		}
+	}
+	record.Input.Headers = headers
+
+	queries := make(map[string]string)
+	for name, value := range record.Input.Queries {
+		if !isSecretName(name) {
+			queries[name] = value
+		}
+	}
+	record.Input.Queries = queries
+
+	u, err := url.Parse(record.Url)
+	if err == nil {
+		values := u.Query()
+		for name := range values {
+			if isSecretName(name) {
+				values.Del(name)
+			}
+		}
+		u.RawQuery = values.Encode()
+		record.Url = u.String()
+	}
+
+	if record.Output != nil {
+		for name := range record.Output.Headers {
+			if isSecretName(name) {
+				delete(record.Output.Headers, name)
+			}
+		}
+	}
+}
+
+func isSecretName(name string) bool {
+	switch strings.ToLower(name) {
+	case types.TokenName, "authorization", "proxy-authorization", "cookie", "set-cookie":
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,133 @@
+package web
+
+import (
+	"fmt"
+	"github.com/csby/wsf/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTry_Curl(t *testing.T) {
+	input := &types.DocTryInput{
+		Params:  map[string]string{"id": "a b"},
+		Headers: map[string]string{"token": "t1", "content-type": "application/json"},
+		Queries: map[string]string{"detail": "true"},
+		Body:    `{"name": "it's"}`,
+	}
+	url, err := tryUrl("/api/user/:id", input, "https://127.0.0.1:8443")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if url != "https://127.0.0.1:8443/api/user/a%20b?detail=true" {
+		t.Fatal("invalid url:", url)
+	}
+	_, err = tryUrl("/api/user/:id/:name", input, "https://127.0.0.1:8443")
+	if err == nil {
+		t.Fatal("error expected for empty path parameter")
+	}
+	fileUrl, err := tryUrl("/api/file/*path", &types.DocTryInput{Params: map[string]string{"path": "/a b/c#d?.txt"}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fileUrl != "/api/file/a%20b/c%23d%3F.txt" {
+		t.Fatal("invalid url:", fileUrl)
+	}
+
+	curl := toCurl(&types.DocTryRecord{Method: "POST", Url: url, Input: *input})
+	expect := `curl -X POST 'https://127.0.0.1:8443/api/user/a%20b?detail=true' -H 'content-type: application/json' -H 'token: t1' --data-raw '{"name": "it'\''s"}'`
+	if curl != expect {
+		t.Fatal("invalid curl:", curl)
+	}
+}
+
+func TestTry_Store(t *testing.T) {
+	folder, err := ioutil.TempDir("", "wsf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+
+	folder = filepath.Join(folder, "doc.store")
+	store, err := newTryStore(folder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.AddHistory("qa", &types.DocTryRecord{ID: "h1", FunctionID: "f1"})
+	store.AddHistory("qa", &types.DocTryRecord{ID: "h2", FunctionID: "f2"})
+	store.Save("qa", &types.DocTryRecord{ID: "s1", FunctionID: "f1", Name: "login"})
+	store.Save("qa", &types.DocTryRecord{ID: "s2", FunctionID: "f1", Name: "login"})
+
+	store, err = newTryStore(folder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	history := store.History("qa", "")
+	if len(history) != 2 || history[0].ID != "h2" {
+		t.Fatal("history should be loaded from file, newest first")
+	}
+	if len(store.History("dev", "")) != 0 {
+		t.Fatal("history should be kept per user")
+	}
+	saved := store.Saved("qa", "f1")
+	if len(saved) != 1 || saved[0].ID != "s1" {
+		t.Fatal("saved request with same name should be replaced")
+	}
+	count, _ := store.ClearHistory("qa", "f1")
+	if count != 1 {
+		t.Fatal("expect 1 cleared, but got", count)
+	}
+	ok, _ := store.DeleteSaved("qa", "s1")
+	if !ok {
+		t.Fatal("saved request should be deleted")
+	}
+}
+
+func TestTry_StoreSize(t *testing.T) {
+	store, err := newTryStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := strings.Repeat("x", tryUserMaxSize/10)
+	for i := 0; i < 20; i++ {
+		store.AddHistory("qa", &types.DocTryRecord{ID: fmt.Sprint(i), Input: types.DocTryInput{Body: body}})
+	}
+	history := store.History("qa", "")
+	if len(history) >= 10 || history[0].ID != "19" {
+		t.Fatal("oldest history should be dropped when size exceeded, count:", len(history))
+	}
+}
+
+func TestTry_Sanitize(t *testing.T) {
+	record := &types.DocTryRecord{
+		Url: "http://127.0.0.1:8080/api?id=1&token=t1",
+		Input: types.DocTryInput{
+			Headers: map[string]string{"Token": "t1", "Authorization": "Bearer t2", "content-type": "application/json"},
+			Queries: map[string]string{"id": "1", "token": "t1"},
+		},
+		Output: &types.DocTryOutput{Headers: map[string]string{"Set-Cookie": "s=1"}},
+	}
+	sanitizeTryRecord(record)
+	if len(record.Input.Headers) != 1 || len(record.Input.Queries) != 1 {
+		t.Fatal("token headers and queries should be removed:", record.Input)
+	}
+	if record.Url != "http://127.0.0.1:8080/api?id=1" {
+		t.Fatal("token should be removed from url:", record.Url)
+	}
+	if len(record.Output.Headers) != 0 {
+		t.Fatal("cookie should be removed from output")
+	}
+}
+
+func TestTry_Base(t *testing.T) {
+	ctrl := &controller{urls: map[string]string{"http": "http://127.0.0.1:8080"}}
+	if ctrl.tryBase("https") != "http://127.0.0.1:8080" {
+		t.Fatal("configured listener should be used")
+	}
+	ctrl.urls = nil
+	if ctrl.tryBase("http") != "" {
+		t.Fatal("empty base expected without listener")
+	}
+}
@@ -69,6 +69,32 @@ func (s *HttpHandlerExtend) DocumentRoot() string {
 	return cfg.Server.Document.Root
 }
 
+func (s *HttpHandlerExtend) DocumentStore() string {
+	return cfg.Server.Document.Store
+}
+
+// 接口文档调试的凭证验证, 示例中凭证即为账号
+func (s *HttpHandlerExtend) DocumentTryCheck() types.RouterPreHandle {
+	return func(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) bool {
+		token := a.Token()
+		if len(token) < 1 {
+			a.Error(types.ErrTokenEmpty)
+			return true
+		}
+		a.Set(types.RouterKeyAccount, token)
+
+		return false
+	}
+}
+
 func (s *HttpHandlerExtend) ServerInfo() *types.ServerInformation {
 	return &types.ServerInformation{Name: "unit-test", Version: "1.0.1.0"}
 }
+
+func (s *HttpHandlerExtend) SocketChannels() types.SocketChannelCollection {
+	return nil
+}
+
+func (s *HttpHandlerExtend) AccessLog() types.AccessLog {
+	return nil
+}
@@ -0,0 +1,156 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/csby/wsf/types"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	accessTimeLayout = "02/Jan/2006:15:04:05 -0700"
+)
+
+// 访问日志, 格式:
+// common: 127.0.0.1 - admin [01/May/2020:08:00:00 +0800] "POST /opt.api/login HTTP/1.1" 200 128
+// combined: common + "referer" "user-agent"
+// json: {"time":"...","rid":1,"rip":"127.0.0.1","account":"admin","method":"POST","uri":"/opt.api/login",...}
+type AccessWriter struct {
+	Rotation Rotation
+
+	format string
+	file   *RotateFile
+	mu     sync.Mutex
+}
+
+// folder为空时输出至标准输出
+func (s *AccessWriter) Init(format string, prefix, folder string) error {
+	format = strings.ToLower(format)
+	switch format {
+	case "":
+		format = types.AccessFormatCommon
+	case types.AccessFormatCommon, types.AccessFormatCombined, types.AccessFormatJson:
+	default:
+		return fmt.Errorf("invalid access log format: %s", format)
+	}
+	s.format = format
+
+	if folder != "" {
+		s.file = NewRotateFile(prefix, folder, s.Rotation)
+	}
+
+	return nil
+}
+
+func (s *AccessWriter) Close() {
+	if s.file != nil {
+		s.file.Close()
+	}
+}
+
+func (s *AccessWriter) Write(entry *types.AccessEntry) {
+	if entry == nil {
+		return
+	}
+
+	var line []byte
+	if s.format == types.AccessFormatJson {
+		line = s.json(entry)
+	} else {
+		line = s.text(entry)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var writer io.Writer = os.Stdout
+	if s.file != nil {
+		writer = s.file
+	}
+	writer.Write(line)
+}
+
+func (s *AccessWriter) text(entry *types.AccessEntry) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteString(s.value(entry.RIP))
+	buf.WriteString(" - ")
+	buf.WriteString(s.value(entry.Account))
+	buf.WriteString(" [")
+	buf.WriteString(entry.Time.Format(accessTimeLayout))
+	buf.WriteString("] \"")
+	buf.WriteString(s.escape(fmt.Sprintf("%s %s %s", entry.Method, entry.Uri, entry.Proto)))
+	buf.WriteString("\" ")
+	buf.WriteString(fmt.Sprint(entry.Status))
+	buf.WriteString(" ")
+	if entry.Size > 0 {
+		buf.WriteString(fmt.Sprint(entry.Size))
+	} else {
+		buf.WriteString("-")
+	}
+	if s.format == types.AccessFormatCombined {
+		buf.WriteString(" \"")
+		buf.WriteString(s.escape(s.value(entry.Referer)))
+		buf.WriteString("\" \"")
+		buf.WriteString(s.escape(s.value(entry.UserAgent)))
+		buf.WriteString("\"")
+	}
+	buf.WriteString("\n")
+
+	return buf.Bytes()
+}
+
+func (s *AccessWriter) json(entry *types.AccessEntry) []byte {
+	data, err := json.Marshal(&struct {
+		Time      string `json:"time"`
+		RID       uint64 `json:"rid"`
+		RIP       string `json:"rip"`
+		Account   string `json:"account,omitempty"`
+		Method    string `json:"method"`
+		Schema    string `json:"schema"`
+		Host      string `json:"host"`
+		Uri       string `json:"uri"`
+		Proto     string `json:"proto"`
+		Status    int    `json:"status"`
+		Size      int64  `json:"size"`
+		Elapsed   int64  `json:"elapsed"`
+		Referer   string `json:"referer,omitempty"`
+		UserAgent string `json:"userAgent,omitempty"`
+	}{
+		Time:      entry.Time.Format(jsonTimeLayout),
+		RID:       entry.RID,
+		RIP:       entry.RIP,
+		Account:   entry.Account,
+		Method:    entry.Method,
+		Schema:    entry.Schema,
+		Host:      entry.Host,
+		Uri:       entry.Uri,
+		Proto:     entry.Proto,
+		Status:    entry.Status,
+		Size:      entry.Size,
+		Elapsed:   int64(entry.Elapsed / time.Millisecond),
+		Referer:   entry.Referer,
+		UserAgent: entry.UserAgent,
+	})
+	if err != nil {
+		return nil
+	}
+
+	return append(data, '\n')
+}
+
+func (s *AccessWriter) value(v string) string {
+	if v == "" {
+		return "-"
+	}
+
+	return v
+}
+
+func (s *AccessWriter) escape(v string) string {
+	return strings.Replace(strings.Replace(v, `\`, `\\`, -1), `"`, `\"`, -1)
+}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"encoding/json"
+	"github.com/csby/wsf/types"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAccessWriter_Format(t *testing.T) {
+	entry := &types.AccessEntry{
+		RID:       1,
+		RIP:       "127.0.0.1",
+		Account:   "admin",
+		Time:      time.Date(2020, 5, 1, 8, 0, 0, 0, time.UTC),
+		Elapsed:   25 * time.Millisecond,
+		Method:    "POST",
+		Uri:       "/opt.api/login?x=1",
+		Proto:     "HTTP/1.1",
+		Status:    200,
+		Size:      128,
+		UserAgent: `curl "7.0"`,
+	}
+
+	writer := &AccessWriter{}
+	err := writer.Init(types.AccessFormatCombined, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := string(writer.text(entry))
+	expected := `127.0.0.1 - admin [01/May/2020:08:00:00 +0000] "POST /opt.api/login?x=1 HTTP/1.1" 200 128 "-" "curl \"7.0\""` + "\n"
+	if line != expected {
+		t.Fatal("invalid line:", line)
+	}
+
+	writer.Init(types.AccessFormatJson, "", "")
+	v := make(map[string]interface{})
+	err = json.Unmarshal(writer.json(entry), &v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v["status"] != float64(200) || v["elapsed"] != float64(25) || v["account"] != "admin" {
+		t.Fatal("invalid json:", v)
+	}
+
+	err = writer.Init("xml", "", "")
+	if err == nil || !strings.Contains(err.Error(), "xml") {
+		t.Fatal("expect invalid format error")
+	}
+}
@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"github.com/csby/wsf/server/configure"
+)
+
+// 根据配置生成日志文件分割及清理规则
+func NewRotation(cfg configure.Rotation) Rotation {
+	return Rotation{
+		MaxSize:  cfg.MaxSize,
+		MaxAge:   cfg.MaxAge,
+		MaxCount: cfg.MaxCount,
+		Compress: cfg.Compress,
+	}
+}
+
+// 根据配置创建日志, cfg为nil时输出所有等级至标准输出
+func NewWriter(cfg *configure.Log, prefix string) *Writer {
+	if cfg == nil {
+		return &Writer{Level: LevelAll, Std: true}
+	}
+
+	instance := &Writer{Rotation: NewRotation(cfg.Rotation)}
+	instance.Init(cfg.Level, prefix, cfg.Folder)
+
+	return instance
+}
+
+// 根据配置创建结构化日志, cfg为nil时输出所有等级至标准输出
+func NewJsonWriter(cfg *configure.Log, prefix string) *JsonWriter {
+	if cfg == nil {
+		return &JsonWriter{Level: LevelAll, Std: true}
+	}
+
+	instance := &JsonWriter{Rotation: NewRotation(cfg.Rotation)}
+	instance.Init(cfg.Level, prefix, cfg.Folder)
+
+	return instance
+}
+
+// 根据配置创建访问日志, 未启用时返回nil
+func NewAccessWriter(cfg *configure.AccessLog, prefix string) (*AccessWriter, error) {
+	if cfg == nil || !cfg.Enabled {
+		return nil, nil
+	}
+
+	instance := &AccessWriter{Rotation: NewRotation(cfg.Rotation)}
+	err := instance.Init(cfg.Format, prefix, cfg.Folder)
+	if err != nil {
+		return nil, err
+	}
+
+	return instance, nil
+}
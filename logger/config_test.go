@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"github.com/csby/wsf/server/configure"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewWriter_Rotation(t *testing.T) {
+	folder, err := ioutil.TempDir("", "wsf-log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+
+	cfg := &configure.Log{Folder: folder, Level: "info"}
+	cfg.MaxSize = 1
+	cfg.MaxCount = 1
+	cfg.Compress = true
+
+	writer := NewWriter(cfg, "svc")
+	if writer.Rotation != NewRotation(cfg.Rotation) {
+		t.Fatal("rotation should be copied from configure:", writer.Rotation)
+	}
+	line := strings.Repeat("a", megabyte*2/3)
+	for index := 0; index < 3; index++ {
+		writer.Info(line)
+	}
+	writer.Close()
+
+	files, err := filepath.Glob(filepath.Join(folder, "svc_*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatal("expect 2 files, but got", files)
+	}
+
+	jsonWriter := NewJsonWriter(cfg, "svc")
+	if jsonWriter.Rotation != writer.Rotation {
+		t.Fatal("rotation of json writer should be copied from configure:", jsonWriter.Rotation)
+	}
+	jsonWriter.Close()
+}
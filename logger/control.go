@@ -0,0 +1,158 @@
+package logger
+
+import (
+	"fmt"
+	"github.com/csby/wsf/types"
+	"sync"
+	"time"
+)
+
+// 运行时级别, 可临时替换配置的级别并在到期后自动恢复
+type levelControl struct {
+	mutex     sync.RWMutex
+	override  bool
+	level     Level
+	expires   *time.Time
+	timer     *time.Timer
+	version   uint64
+	listeners []func(level *types.LogLevel)
+}
+
+func (s *levelControl) enabled(def, l Level) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.override {
+		return s.level&l != 0
+	}
+
+	return def&l != 0
+}
+
+func (s *levelControl) get(def Level) *types.LogLevel {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.info(def)
+}
+
+// 替换级别, duration小于等于0时不自动恢复, changed用于输出变更日志
+func (s *levelControl) set(def, level Level, duration time.Duration, changed func(info *types.LogLevel)) *types.LogLevel {
+	s.mutex.Lock()
+	s.stop()
+	s.override = true
+	s.level = level
+	if duration > 0 {
+		expires := time.Now().Add(duration)
+		s.expires = &expires
+		version := s.version
+		s.timer = time.AfterFunc(duration, func() {
+			s.revert(def, version, changed)
+		})
+	}
+	info := s.info(def)
+	s.mutex.Unlock()
+
+	s.changed(info, changed)
+
+	return info
+}
+
+// 恢复为配置的级别
+func (s *levelControl) reset(def Level, changed func(info *types.LogLevel)) *types.LogLevel {
+	s.mutex.Lock()
+	s.stop()
+	s.override = false
+	info := s.info(def)
+	s.mutex.Unlock()
+
+	s.changed(info, changed)
+
+	return info
+}
+
+func (s *levelControl) addListener(listener func(level *types.LogLevel)) {
+	if listener == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.listeners = append(s.listeners, listener)
+}
+
+func (s *levelControl) revert(def Level, version uint64, changed func(info *types.LogLevel)) {
+	s.mutex.Lock()
+	if s.version != version {
+		// 已被再次修改
+		s.mutex.Unlock()
+		return
+	}
+	s.timer = nil
+	s.expires = nil
+	s.override = false
+	info := s.info(def)
+	s.mutex.Unlock()
+
+	s.changed(info, changed)
+}
+
+func (s *levelControl) changed(info *types.LogLevel, changed func(info *types.LogLevel)) {
+	if changed != nil {
+		changed(info)
+	}
+
+	s.mutex.RLock()
+	listeners := s.listeners
+	s.mutex.RUnlock()
+	for _, listener := range listeners {
+		listener(info)
+	}
+}
+
+func (s *levelControl) stop() {
+	s.version++
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.expires = nil
+}
+
+func (s *levelControl) info(def Level) *types.LogLevel {
+	info := &types.LogLevel{
+		Level:   def.String(),
+		Default: def.String(),
+	}
+	if s.override {
+		info.Level = s.level.String()
+	}
+	if s.expires != nil {
+		expires := types.DateTime(*s.expires)
+		info.Expires = &expires
+	}
+
+	return info
+}
+
+func parseLevel(level string) (Level, error) {
+	var v Level
+	v.Parse(level)
+	if v == 0 {
+		return 0, fmt.Errorf("invalid level '%s'", level)
+	}
+
+	return v, nil
+}
+
+func levelChangedText(info *types.LogLevel) string {
+	if info.Expires != nil {
+		return fmt.Sprintf("log level changed to '%s' until %s, default '%s'", info.Level, info.Expires.String(), info.Default)
+	}
+	if info.Level != info.Default {
+		return fmt.Sprintf("log level changed to '%s', default '%s'", info.Level, info.Default)
+	}
+
+	return fmt.Sprintf("log level reverted to default '%s'", info.Default)
+}
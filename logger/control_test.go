@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"github.com/csby/wsf/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriter_SetLevel(t *testing.T) {
+	folder, err := ioutil.TempDir("", "wsf-log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+
+	writer := &Writer{}
+	writer.Init("error|warning", "svc", folder)
+	defer writer.Close()
+
+	changes := make(chan *types.LogLevel, 4)
+	writer.AddLevelListener(func(level *types.LogLevel) {
+		changes <- level
+	})
+
+	_, err = writer.SetLevel("unknown", time.Minute)
+	if err == nil {
+		t.Fatal("invalid level should be rejected")
+	}
+
+	writer.Debug("before")
+	level, err := writer.SetLevel("error|warning|debug", 100*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if level.Level != "error|warning|debug" || level.Default != "error|warning" || level.Expires == nil {
+		t.Fatal("invalid level:", level)
+	}
+	writer.Debug("during")
+
+	select {
+	case <-changes:
+	case <-time.After(time.Second):
+		t.Fatal("listener not called on set")
+	}
+	select {
+	case level = <-changes:
+		if level.Level != "error|warning" || level.Expires != nil {
+			t.Fatal("invalid reverted level:", level)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("level not reverted")
+	}
+	writer.Debug("after")
+
+	level = writer.GetLevel()
+	if level.Level != level.Default {
+		t.Fatal("level not reverted:", level)
+	}
+
+	files, err := filepath.Glob(filepath.Join(folder, "svc_*.log"))
+	if err != nil || len(files) != 1 {
+		t.Fatal("expect 1 file, but got", files, err)
+	}
+	data, err := ioutil.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(data)
+	if strings.Contains(text, "before") || strings.Contains(text, "after") || !strings.Contains(text, "during") {
+		t.Fatal("invalid output:", text)
+	}
+	if !strings.Contains(text, "log level changed") || !strings.Contains(text, "log level reverted") {
+		t.Fatal("level change not logged:", text)
+	}
+}
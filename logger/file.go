@@ -0,0 +1,305 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	megabyte = 1024 * 1024
+)
+
+// 日志文件分割及清理规则, 零值表示仅按日期分割且不清理
+type Rotation struct {
+	MaxSize  int  // 单个文件最大大小(MB), 超过时分割, 0表示不限制
+	MaxAge   int  // 文件保留天数, 0表示不限制
+	MaxCount int  // 最多保留的历史文件数(不含当前文件), 0表示不限制
+	Compress bool // 是否压缩(gzip)已分割的文件
+}
+
+// 按日期及大小分割的文件, 用于访问日志及审计记录等
+type RotateFile struct {
+	rotation Rotation
+	file     dailyFile
+}
+
+func NewRotateFile(prefix, folder string, rotation Rotation) *RotateFile {
+	instance := &RotateFile{rotation: rotation}
+	instance.file.init(prefix, folder, &instance.rotation)
+
+	return instance
+}
+
+func (s *RotateFile) Write(p []byte) (int, error) {
+	return s.file.Write(p)
+}
+
+func (s *RotateFile) Close() error {
+	s.file.close()
+
+	return nil
+}
+
+// 获取文件夹中以prefix为前缀的日志文件(包括已分割及压缩的文件), 按修改时间从新到旧排列
+func Files(folder, prefix string) ([]os.FileInfo, error) {
+	infos, err := ioutil.ReadDir(folder)
+	if err != nil {
+		return nil, err
+	}
+
+	if prefix != "" {
+		prefix = fmt.Sprintf("%s_", prefix)
+	}
+	pattern := regexp.MustCompile(fmt.Sprintf(`^%s\d{4}-\d{1,2}-\d{1,2}(\.\d+)?\.log(\.gz)?$`, regexp.QuoteMeta(prefix)))
+	files := make([]os.FileInfo, 0)
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+		if pattern.MatchString(info.Name()) {
+			files = append(files, info)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().After(files[j].ModTime())
+	})
+
+	return files, nil
+}
+
+// 按日期分割的日志文件: folder/prefix_YYYY-M-D.log
+// 超过大小时分割为: folder/prefix_YYYY-M-D.N.log(.gz)
+type dailyFile struct {
+	prefix   string
+	folder   string
+	rotation *Rotation
+
+	year  int
+	month time.Month
+	day   int
+	name  string
+	size  int64
+	file  *os.File
+	mu    sync.Mutex
+
+	cleaning sync.WaitGroup
+	cleanMu  sync.Mutex
+}
+
+func (s *dailyFile) init(prefix, folder string, rotation *Rotation) {
+	s.prefix = prefix
+	s.folder = folder
+	s.rotation = rotation
+}
+
+func (s *dailyFile) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.file == nil || s.year != now.Year() || s.month != now.Month() || s.day != now.Day() {
+		rotated := ""
+		if s.file != nil {
+			rotated = filepath.Join(s.folder, s.name)
+		}
+		err := s.open(now)
+		if err != nil {
+			return 0, err
+		}
+		if rotated != "" {
+			s.afterRotate(rotated)
+		}
+	} else if s.maxSize() > 0 && s.size > 0 && s.size+int64(len(p)) > s.maxSize() {
+		err := s.rotate(now)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := s.file.Write(p)
+	s.size += int64(n)
+
+	return n, err
+}
+
+// 关闭文件, 并等待压缩及清理完成
+func (s *dailyFile) close() {
+	s.mu.Lock()
+	if s.file != nil {
+		s.file.Close()
+		s.file = nil
+	}
+	s.mu.Unlock()
+
+	s.cleaning.Wait()
+}
+
+func (s *dailyFile) open(now time.Time) error {
+	if s.file != nil {
+		s.file.Close()
+		s.file = nil
+	}
+
+	s.year = now.Year()
+	s.month = now.Month()
+	s.day = now.Day()
+	s.name = fmt.Sprint(s.namePrefix(), now.Year(), "-", int(now.Month()), "-", now.Day(), ".log")
+
+	os.MkdirAll(s.folder, 0777)
+	file, err := os.OpenFile(filepath.Join(s.folder, s.name), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		return err
+	}
+	s.file = file
+	s.size = 0
+	info, err := file.Stat()
+	if err == nil {
+		s.size = info.Size()
+	}
+
+	return nil
+}
+
+// 将当前文件重命名为prefix_YYYY-M-D.N.log并重新打开
+func (s *dailyFile) rotate(now time.Time) error {
+	s.file.Close()
+	s.file = nil
+
+	current := filepath.Join(s.folder, s.name)
+	base := current[:len(current)-len(".log")]
+	rotated := ""
+	for index := 1; ; index++ {
+		rotated = fmt.Sprintf("%s.%d.log", base, index)
+		if !s.exist(rotated) && !s.exist(rotated+".gz") {
+			break
+		}
+	}
+	err := os.Rename(current, rotated)
+	if err != nil {
+		rotated = ""
+	}
+
+	err = s.open(now)
+	if err != nil {
+		return err
+	}
+	if rotated != "" {
+		s.afterRotate(rotated)
+	}
+
+	return nil
+}
+
+// 异步压缩已分割的文件并清理过期文件
+func (s *dailyFile) afterRotate(filePath string) {
+	if s.rotation == nil {
+		return
+	}
+	if !s.rotation.Compress && s.rotation.MaxAge < 1 && s.rotation.MaxCount < 1 {
+		return
+	}
+
+	current := s.name
+	s.cleaning.Add(1)
+	go func() {
+		defer s.cleaning.Done()
+
+		s.cleanMu.Lock()
+		defer s.cleanMu.Unlock()
+
+		if s.rotation.Compress {
+			compress(filePath)
+		}
+		s.clean(current)
+	}()
+}
+
+func (s *dailyFile) clean(current string) {
+	if s.rotation.MaxAge < 1 && s.rotation.MaxCount < 1 {
+		return
+	}
+
+	files, err := Files(s.folder, s.prefix)
+	if err != nil {
+		return
+	}
+	for index, info := range files {
+		if info.Name() == current {
+			files = append(files[:index], files[index+1:]...)
+			break
+		}
+	}
+
+	expired := time.Now().Add(-time.Duration(s.rotation.MaxAge) * 24 * time.Hour)
+	for index, info := range files {
+		if s.rotation.MaxCount > 0 && index >= s.rotation.MaxCount {
+			os.Remove(filepath.Join(s.folder, info.Name()))
+		} else if s.rotation.MaxAge > 0 && info.ModTime().Before(expired) {
+			os.Remove(filepath.Join(s.folder, info.Name()))
+		}
+	}
+}
+
+func (s *dailyFile) maxSize() int64 {
+	if s.rotation == nil {
+		return 0
+	}
+
+	return int64(s.rotation.MaxSize) * megabyte
+}
+
+func (s *dailyFile) namePrefix() string {
+	if s.prefix != "" {
+		return fmt.Sprintf("%s_", s.prefix)
+	}
+
+	return ""
+}
+
+func (s *dailyFile) exist(filePath string) bool {
+	_, err := os.Stat(filePath)
+	return err == nil
+}
+
+// 压缩为filePath.gz并删除原文件
+func compress(filePath string) error {
+	src, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	dst, err := os.OpenFile(filePath+".gz", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+
+	writer := gzip.NewWriter(dst)
+	writer.Name = info.Name()
+	writer.ModTime = info.ModTime()
+	_, err = io.Copy(writer, src)
+	if err == nil {
+		err = writer.Close()
+	}
+	dst.Close()
+	if err != nil {
+		os.Remove(filePath + ".gz")
+		return err
+	}
+	src.Close()
+	os.Chtimes(filePath+".gz", info.ModTime(), info.ModTime())
+
+	return os.Remove(filePath)
+}
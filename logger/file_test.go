@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriter_Rotation(t *testing.T) {
+	folder, err := ioutil.TempDir("", "wsf-log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+
+	writer := &Writer{
+		Rotation: Rotation{
+			MaxSize:  1,
+			MaxCount: 1,
+			Compress: true,
+		},
+	}
+	writer.Init("info", "svc", folder)
+	line := strings.Repeat("a", megabyte*2/3)
+	for index := 0; index < 3; index++ {
+		writer.Info(line)
+	}
+	writer.Close()
+
+	files, err := filepath.Glob(filepath.Join(folder, "svc_*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatal("expect 2 files, but got", files)
+	}
+	compressed := ""
+	for _, file := range files {
+		if strings.HasSuffix(file, ".2.log.gz") {
+			compressed = file
+		} else if strings.Count(filepath.Base(file), ".") != 1 {
+			t.Fatal("unexpected file:", file)
+		}
+	}
+	if compressed == "" {
+		t.Fatal("rotated file not compressed:", files)
+	}
+
+	file, err := os.Open(compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), line) {
+		t.Fatal("invalid compressed content")
+	}
+}
@@ -0,0 +1,228 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/csby/wsf/types"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+)
+
+const (
+	JsonKeyTime   = "time"
+	JsonKeyLevel  = "level"
+	JsonKeyCaller = "caller"
+	JsonKeyMsg    = "msg"
+	JsonKeyLogger = "logger"
+
+	// 键值对个数为奇数时, 最后一个值的键名
+	JsonKeyBad = "!BADKEY"
+
+	jsonTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// 结构化日志, 每行输出一个JSON对象, 如:
+// {"time":"2020-05-01T08:00:00.000+08:00","level":"info","caller":"server/handler.go:120","msg":"http request","rid":"1","path":"/api/login"}
+// 与预留字段(time, level, caller, msg, logger)同名的键添加"fields."前缀
+type JsonWriter struct {
+	Level    Level
+	Std      bool
+	Rotation Rotation
+
+	prefix string
+	folder string
+
+	file dailyFile
+	mu   sync.Mutex
+	tail tailListeners
+
+	control levelControl
+}
+
+func (s *JsonWriter) Init(level string, prefix, folder string) error {
+	s.Level.Parse(level)
+	s.prefix = prefix
+	s.folder = folder
+	s.file.init(prefix, folder, &s.Rotation)
+
+	return nil
+}
+
+func (s *JsonWriter) Close() {
+	s.file.close()
+}
+
+func (s *JsonWriter) Error(v ...interface{}) string {
+	return s.output(LevelError, fmt.Sprint(v...), nil)
+}
+
+func (s *JsonWriter) Warning(v ...interface{}) string {
+	return s.output(LevelWarning, fmt.Sprint(v...), nil)
+}
+
+func (s *JsonWriter) Info(v ...interface{}) string {
+	return s.output(LevelInfo, fmt.Sprint(v...), nil)
+}
+
+func (s *JsonWriter) Trace(v ...interface{}) string {
+	return s.output(LevelTrace, fmt.Sprint(v...), nil)
+}
+
+func (s *JsonWriter) Debug(v ...interface{}) string {
+	return s.output(LevelDebug, fmt.Sprint(v...), nil)
+}
+
+func (s *JsonWriter) ErrorKV(msg string, kv ...interface{}) string {
+	return s.output(LevelError, msg, kv)
+}
+
+func (s *JsonWriter) WarningKV(msg string, kv ...interface{}) string {
+	return s.output(LevelWarning, msg, kv)
+}
+
+func (s *JsonWriter) InfoKV(msg string, kv ...interface{}) string {
+	return s.output(LevelInfo, msg, kv)
+}
+
+func (s *JsonWriter) TraceKV(msg string, kv ...interface{}) string {
+	return s.output(LevelTrace, msg, kv)
+}
+
+func (s *JsonWriter) DebugKV(msg string, kv ...interface{}) string {
+	return s.output(LevelDebug, msg, kv)
+}
+
+func (s *JsonWriter) AddTailListener(listener func(entry *types.LogEntry)) {
+	s.tail.add(listener)
+}
+
+func (s *JsonWriter) GetLevel() *types.LogLevel {
+	return s.control.get(s.Level)
+}
+
+func (s *JsonWriter) SetLevel(level string, duration time.Duration) (*types.LogLevel, error) {
+	v, err := parseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.control.set(s.Level, v, duration, s.levelChanged), nil
+}
+
+func (s *JsonWriter) ResetLevel() *types.LogLevel {
+	return s.control.reset(s.Level, s.levelChanged)
+}
+
+func (s *JsonWriter) AddLevelListener(listener func(level *types.LogLevel)) {
+	s.control.addListener(listener)
+}
+
+// 级别变更总是输出
+func (s *JsonWriter) levelChanged(info *types.LogLevel) {
+	now := time.Now()
+	m := levelChangedText(info)
+	s.write(s.format(now, LevelWarning, "", m, []interface{}{"level.active", info.Level, "level.default", info.Default}))
+	s.tail.notify(now, LevelWarning, "", m)
+}
+
+func (s *JsonWriter) getWriter() io.Writer {
+	if s.folder != "" {
+		return &s.file
+	}
+
+	return os.Stderr
+}
+
+func (s *JsonWriter) output(l Level, m string, kv []interface{}) string {
+	str := fmt.Sprintf("%s; %s", levelText[l], m)
+
+	if s.control.enabled(s.Level, l) {
+		now := time.Now()
+		at := caller(4)
+		s.write(s.format(now, l, at, m, kv))
+		s.tail.notify(now, l, at, types.LogKVText(m, kv...))
+	}
+
+	return str
+}
+
+func (s *JsonWriter) write(line []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Std && s.folder != "" {
+		os.Stdout.Write(line)
+	}
+
+	s.getWriter().Write(line)
+}
+
+func (s *JsonWriter) format(t time.Time, l Level, caller, m string, kv []interface{}) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteString("{")
+	s.writeField(buf, JsonKeyTime, t.Format(jsonTimeLayout), true)
+	s.writeField(buf, JsonKeyLevel, levelText[l], false)
+	if caller != "" {
+		s.writeField(buf, JsonKeyCaller, caller, false)
+	}
+	s.writeField(buf, JsonKeyMsg, m, false)
+	if s.prefix != "" {
+		s.writeField(buf, JsonKeyLogger, s.prefix, false)
+	}
+
+	count := len(kv)
+	for index := 0; index < count; index += 2 {
+		if index+1 < count {
+			s.writeField(buf, s.key(kv[index]), kv[index+1], false)
+		} else {
+			s.writeField(buf, JsonKeyBad, kv[index], false)
+		}
+	}
+	buf.WriteString("}\n")
+
+	return buf.Bytes()
+}
+
+func (s *JsonWriter) key(v interface{}) string {
+	key := fmt.Sprint(v)
+	switch key {
+	case JsonKeyTime, JsonKeyLevel, JsonKeyCaller, JsonKeyMsg, JsonKeyLogger:
+		return "fields." + key
+	default:
+		return key
+	}
+}
+
+func (s *JsonWriter) writeField(buf *bytes.Buffer, key string, value interface{}, first bool) {
+	if !first {
+		buf.WriteString(",")
+	}
+	name, _ := json.Marshal(key)
+	buf.Write(name)
+	buf.WriteString(":")
+
+	// error序列化后为{}, 改为输出错误信息
+	if err, ok := value.(error); ok {
+		value = err.Error()
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(value))
+	}
+	buf.Write(data)
+}
+
+// 调用位置, 如: server/handler.go:120
+func caller(skip int) string {
+	_, file, line, ok := runtime.Caller(skip)
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprintf("%s/%s:%d", filepath.Base(filepath.Dir(file)), filepath.Base(file), line)
+}
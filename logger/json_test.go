@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/csby/wsf/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJsonWriter(t *testing.T) {
+	folder, err := ioutil.TempDir("", "wsf-log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+
+	writer := &JsonWriter{}
+	writer.Init("error|info", "svc", folder)
+	defer writer.Close()
+
+	base := &types.Base{}
+	base.SetLog(writer)
+	text := base.LogInfoKV("http request", "rid", 1, "path", "/api/login", "msg", "dup", "err", fmt.Errorf("failed"), "odd")
+	if text != "info; http request" {
+		t.Fatal("invalid text:", text)
+	}
+	base.LogError("error", 1)
+	base.LogDebug("ignored")
+
+	files, err := filepath.Glob(filepath.Join(folder, "svc_*.log"))
+	if err != nil || len(files) != 1 {
+		t.Fatal("expect 1 file, but got", files, err)
+	}
+	file, err := os.Open(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	lines := make([]map[string]interface{}, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := make(map[string]interface{})
+		err = json.Unmarshal(scanner.Bytes(), &line)
+		if err != nil {
+			t.Fatal(err, scanner.Text())
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 2 {
+		t.Fatal("expect 2 lines, but got", len(lines))
+	}
+
+	line := lines[0]
+	if line[JsonKeyLevel] != "info" || line[JsonKeyMsg] != "http request" || line[JsonKeyLogger] != "svc" {
+		t.Fatal("invalid line:", line)
+	}
+	if line["rid"] != float64(1) || line["path"] != "/api/login" || line["fields.msg"] != "dup" || line["err"] != "failed" || line[JsonKeyBad] != "odd" {
+		t.Fatal("invalid fields:", line)
+	}
+	caller, _ := line[JsonKeyCaller].(string)
+	if !strings.HasPrefix(caller, "logger/json_test.go:") {
+		t.Fatal("invalid caller:", caller)
+	}
+	if lines[1][JsonKeyMsg] != "error1" {
+		t.Fatal("invalid message:", lines[1][JsonKeyMsg])
+	}
+}
@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/csby/wsf/types"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	textTimeLayout = "2006/01/02 15:04:05"
+)
+
+// 解析Writer或JsonWriter输出的一行日志, 不是日志开始行(如多行内容的后续行)时返回nil
+// Writer: 2020/05/01 08:00:00 writer.go:12: info; message
+// JsonWriter: {"time":"2020-05-01T08:00:00.000+08:00","level":"info","caller":"server/handler.go:120","msg":"message",...}
+func ParseLine(line string) *types.LogEntry {
+	if strings.HasPrefix(line, "{") {
+		return parseJsonLine(line)
+	}
+
+	return parseTextLine(line)
+}
+
+func parseTextLine(line string) *types.LogEntry {
+	if len(line) < len(textTimeLayout)+1 {
+		return nil
+	}
+	t, err := time.ParseInLocation(textTimeLayout, line[:len(textTimeLayout)], time.Local)
+	if err != nil {
+		return nil
+	}
+
+	entry := &types.LogEntry{
+		Time:    types.DateTime(t),
+		Message: strings.TrimSpace(line[len(textTimeLayout):]),
+	}
+	index := strings.Index(entry.Message, ": ")
+	if index > 0 && !strings.Contains(entry.Message[:index], " ") {
+		entry.Caller = entry.Message[:index]
+		entry.Message = entry.Message[index+2:]
+	}
+	index = strings.Index(entry.Message, "; ")
+	if index > 0 {
+		var level Level
+		level.Parse(entry.Message[:index])
+		if level != 0 {
+			entry.Level = entry.Message[:index]
+			entry.Message = entry.Message[index+2:]
+		}
+	}
+
+	return entry
+}
+
+func parseJsonLine(line string) *types.LogEntry {
+	fields := make(map[string]interface{})
+	if json.Unmarshal([]byte(line), &fields) != nil {
+		return nil
+	}
+
+	entry := &types.LogEntry{
+		Level:   fmt.Sprint(fields[JsonKeyLevel]),
+		Message: fmt.Sprint(fields[JsonKeyMsg]),
+	}
+	if v, ok := fields[JsonKeyCaller]; ok {
+		entry.Caller = fmt.Sprint(v)
+	}
+	t, err := time.Parse(time.RFC3339, fmt.Sprint(fields[JsonKeyTime]))
+	if err == nil {
+		entry.Time = types.DateTime(t)
+	}
+
+	// 键值对按名称排序后追加至内容
+	keys := make([]string, 0)
+	for key := range fields {
+		switch key {
+		case JsonKeyTime, JsonKeyLevel, JsonKeyCaller, JsonKeyMsg:
+			continue
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) > 0 {
+		sort.Strings(keys)
+		kv := make([]interface{}, 0, len(keys)*2)
+		for _, key := range keys {
+			kv = append(kv, key, fields[key])
+		}
+		entry.Message = types.LogKVText(entry.Message, kv...)
+	}
+
+	return entry
+}
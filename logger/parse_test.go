@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"github.com/csby/wsf/types"
+	"testing"
+)
+
+func TestParseLine(t *testing.T) {
+	entry := ParseLine("2020/05/01 08:00:00 handler.go:12: warning; connect timeout: 10s")
+	if entry == nil {
+		t.Fatal("parse text line fail")
+	}
+	if entry.Level != "warning" || entry.Caller != "handler.go:12" || entry.Message != "connect timeout: 10s" {
+		t.Fatal("invalid entry:", entry)
+	}
+
+	entry = ParseLine(`{"time":"2020-05-01T08:00:00.000+08:00","level":"info","caller":"server/handler.go:120","msg":"http request","rid":1,"path":"/api/login"}`)
+	if entry == nil {
+		t.Fatal("parse json line fail")
+	}
+	if entry.Level != "info" || entry.Caller != "server/handler.go:120" || entry.Message != "http request path=/api/login rid=1" {
+		t.Fatal("invalid entry:", entry)
+	}
+
+	if ParseLine("goroutine 1 [running]:") != nil {
+		t.Fatal("continuation line should not be parsed")
+	}
+}
+
+func TestWriter_AddTailListener(t *testing.T) {
+	writer := &JsonWriter{}
+	writer.Init("error", "", "")
+
+	entries := make([]*types.LogEntry, 0)
+	writer.AddTailListener(func(entry *types.LogEntry) {
+		entries = append(entries, entry)
+	})
+	writer.ErrorKV("failed", "code", 5)
+	writer.Info("ignored")
+
+	if len(entries) != 1 {
+		t.Fatal("expect 1 entry, but got", len(entries))
+	}
+	if entries[0].Level != "error" || entries[0].Message != "failed code=5" {
+		t.Fatal("invalid entry:", entries[0])
+	}
+}
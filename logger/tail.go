@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"github.com/csby/wsf/types"
+	"sync"
+	"time"
+)
+
+// 实时输出的监听函数
+type tailListeners struct {
+	mutex     sync.RWMutex
+	listeners []func(entry *types.LogEntry)
+}
+
+func (s *tailListeners) add(listener func(entry *types.LogEntry)) {
+	if listener == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.listeners = append(s.listeners, listener)
+}
+
+func (s *tailListeners) notify(t time.Time, l Level, caller, m string) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if len(s.listeners) < 1 {
+		return
+	}
+
+	entry := &types.LogEntry{
+		Time:    types.DateTime(t),
+		Level:   levelText[l],
+		Caller:  caller,
+		Message: m,
+	}
+	for _, listener := range s.listeners {
+		listener(entry)
+	}
+}
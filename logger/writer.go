@@ -2,43 +2,40 @@ package logger
 
 import (
 	"fmt"
+	"github.com/csby/wsf/types"
 	"log"
 	"os"
-	"path/filepath"
-	"sync"
 	"time"
 )
 
 var std = log.New(os.Stdout, "", log.Ldate|log.Ltime|log.Lshortfile)
 
 type Writer struct {
-	Level Level
-	Std   bool
+	Level    Level
+	Std      bool
+	Rotation Rotation
 
 	prefix string
 	folder string
 
 	logger *log.Logger
-	year   int
-	month  time.Month
-	day    int
-	file   *os.File
-	mu     sync.Mutex
+	file   dailyFile
+	tail   tailListeners
+
+	control levelControl
 }
 
 func (s *Writer) Init(level string, prefix, folder string) error {
 	s.Level.Parse(level)
 	s.prefix = prefix
 	s.folder = folder
+	s.file.init(prefix, folder, &s.Rotation)
 
 	return nil
 }
 
 func (s *Writer) Close() {
-	if s.file != nil {
-		s.file.Close()
-		s.file = nil
-	}
+	s.file.close()
 }
 
 func (s *Writer) Error(v ...interface{}) string {
@@ -61,37 +58,35 @@ func (s *Writer) Debug(v ...interface{}) string {
 	return s.output(LevelDebug, fmt.Sprint(v...))
 }
 
+func (s *Writer) AddTailListener(listener func(entry *types.LogEntry)) {
+	s.tail.add(listener)
+}
+
+func (s *Writer) GetLevel() *types.LogLevel {
+	return s.control.get(s.Level)
+}
+
+func (s *Writer) SetLevel(level string, duration time.Duration) (*types.LogLevel, error) {
+	v, err := parseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.control.set(s.Level, v, duration, s.levelChanged), nil
+}
+
+func (s *Writer) ResetLevel() *types.LogLevel {
+	return s.control.reset(s.Level, s.levelChanged)
+}
+
+func (s *Writer) AddLevelListener(listener func(level *types.LogLevel)) {
+	s.control.addListener(listener)
+}
+
 func (s *Writer) getLogger() *log.Logger {
 	if s.folder != "" {
-		now := time.Now()
-		if s.year != now.Year() || s.month != now.Month() || s.day != now.Day() || s.file == nil {
-			s.mu.Lock()
-			defer s.mu.Unlock()
-
-			s.year = now.Year()
-			s.month = now.Month()
-			s.day = now.Day()
-			if s.logger != nil {
-				s.logger = nil
-			}
-			if s.file != nil {
-				s.file.Close()
-				s.file = nil
-			}
-
-			os.MkdirAll(s.folder, 0777)
-			prefix := ""
-			if s.prefix != "" {
-				prefix = fmt.Sprintf("%s_", s.prefix)
-			}
-			fileName := fmt.Sprint(prefix, now.Year(), "-", int(now.Month()), "-", now.Day(), ".log")
-			filePath := filepath.Join(s.folder, fileName)
-			file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
-			if err != nil {
-				return nil
-			}
-			s.file = file
-			s.logger = log.New(s.file, "", log.Ldate|log.Ltime|log.Lshortfile)
+		if s.logger == nil {
+			s.logger = log.New(&s.file, "", log.Ldate|log.Ltime|log.Lshortfile)
 		}
 	} else {
 		if s.logger == nil {
@@ -109,16 +104,29 @@ func (s *Writer) getLogger() *log.Logger {
 func (s *Writer) output(l Level, m string) string {
 	str := fmt.Sprintf("%s; %s", levelText[l], m)
 
-	if s.Level&l != 0 {
-		if s.Std && s.file != nil {
-			std.Output(4, fmt.Sprintln(str))
-		}
-
-		logger := s.getLogger()
-		if logger != nil {
-			logger.Output(4, fmt.Sprintln(str))
-		}
+	if s.control.enabled(s.Level, l) {
+		s.write(5, l, str, m)
 	}
 
 	return str
 }
+
+// depth: 调用位置相对于write的层数
+func (s *Writer) write(depth int, l Level, str, m string) {
+	if s.Std && s.folder != "" {
+		std.Output(depth, fmt.Sprintln(str))
+	}
+
+	logger := s.getLogger()
+	if logger != nil {
+		logger.Output(depth, fmt.Sprintln(str))
+	}
+
+	s.tail.notify(time.Now(), l, caller(depth), m)
+}
+
+// 级别变更总是输出
+func (s *Writer) levelChanged(info *types.LogLevel) {
+	m := levelChangedText(info)
+	s.write(2, LevelWarning, fmt.Sprintf("%s; %s", levelText[LevelWarning], m), m)
+}
@@ -0,0 +1,309 @@
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"github.com/csby/wsf/logger"
+	"github.com/csby/wsf/server/configure"
+	"github.com/csby/wsf/types"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	auditFilePrefix    = "audit"
+	auditDefaultLimit  = 100
+	auditMaxLimit      = 1000
+	auditTargetMaxSize = 1024
+)
+
+type Audit struct {
+	controller
+
+	folder string
+	file   *logger.RotateFile
+}
+
+func NewAudit(log types.Log, cfg *configure.Configure) *Audit {
+	instance := &Audit{}
+	instance.SetLog(log)
+	instance.cfg = cfg
+
+	if cfg != nil && len(cfg.Operation.Audit.Folder) > 0 {
+		instance.folder = cfg.Operation.Audit.Folder
+		instance.file = logger.NewRotateFile(auditFilePrefix, instance.folder, logger.NewRotation(cfg.Operation.Audit.Rotation))
+	}
+
+	return instance
+}
+
+// 审计中间件, 记录操作账号、来源、操作对象及结果, action为操作名称, 如: site.root.upload
+func (s *Audit) Record(action string) types.RouterMiddleware {
+	return func(next types.RouterHandle) types.RouterHandle {
+		return func(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+			if s.file == nil || a == nil {
+				next(w, r, p, a)
+				return
+			}
+
+			input := s.peekInput(r)
+			next(w, r, p, a)
+
+			record := &types.AuditRecord{
+				Time:    types.DateTime(a.EnterTime()),
+				RID:     a.RID(),
+				IP:      a.RIP(),
+				Action:  action,
+				Target:  s.target(r, a, input),
+				Method:  r.Method,
+				Path:    r.URL.Path,
+				Success: !a.IsError(),
+			}
+			if v, ok := a.Get(types.RouterKeyAccount); ok {
+				record.Account = fmt.Sprint(v)
+			}
+			if !record.Success {
+				record.Error = s.outputError(a.GetOutput())
+			}
+
+			err := s.write(record)
+			if err != nil {
+				s.LogError("write audit record fail: ", err)
+			}
+		}
+	}
+}
+
+func (s *Audit) Query(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+	if s.file == nil {
+		a.Error(types.ErrNotSupport, "audit is disabled")
+		return
+	}
+
+	filter := &types.AuditFilter{}
+	err := a.GetJson(filter)
+	if err != nil {
+		a.Error(types.ErrInput, err)
+		return
+	}
+	if filter.Limit < 1 {
+		filter.Limit = auditDefaultLimit
+	} else if filter.Limit > auditMaxLimit {
+		filter.Limit = auditMaxLimit
+	}
+
+	records, err := s.query(filter)
+	if err != nil {
+		a.Error(types.ErrInternal, err)
+		return
+	}
+
+	a.Success(records)
+}
+
+func (s *Audit) QueryDoc(doc types.Doc, method string, path types.HttpPath) {
+	now := types.DateTime(time.Now())
+	catalog := s.createCatalog(doc, "操作审计")
+	function := catalog.AddFunction(method, path, "查询审计记录")
+	function.SetNote("查询上传、删除及重启等操作的审计记录, 按时间从新到旧排列")
+	function.SetInputExample(&types.AuditFilter{
+		Action: "site.",
+		Limit:  100,
+	})
+	function.SetOutputDataExample([]*types.AuditRecord{
+		{
+			Time:    now,
+			RID:     1,
+			Account: "admin",
+			IP:      "192.168.1.100",
+			Action:  "site.root.delete",
+			Target:  `{"name":"test.txt"}`,
+			Method:  "POST",
+			Path:    "/opt.api/site/root/file/delete",
+			Success: true,
+		},
+	})
+	function.AddOutputError(types.ErrNotSupport)
+	function.AddOutputError(types.ErrInput)
+	function.AddOutputError(types.ErrTokenInvalid)
+}
+
+func (s *Audit) write(record *types.AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = s.file.Write(append(data, '\n'))
+
+	return err
+}
+
+func (s *Audit) query(filter *types.AuditFilter) ([]*types.AuditRecord, error) {
+	results := make([]*types.AuditRecord, 0)
+	files, err := logger.Files(s.folder, auditFilePrefix)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return results, nil
+		}
+		return nil, err
+	}
+
+	for _, info := range files {
+		records, err := s.readFile(filepath.Join(s.folder, info.Name()))
+		if err != nil {
+			return nil, err
+		}
+		// 文件内按时间从旧到新记录
+		for index := len(records) - 1; index >= 0; index-- {
+			if !s.match(records[index], filter) {
+				continue
+			}
+			results = append(results, records[index])
+			if len(results) >= filter.Limit {
+				break
+			}
+		}
+		if len(results) >= filter.Limit {
+			break
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return time.Time(results[i].Time).After(time.Time(results[j].Time))
+	})
+
+	return results, nil
+}
+
+func (s *Audit) readFile(filePath string) ([]*types.AuditRecord, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(filePath, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	records := make([]*types.AuditRecord, 0)
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		record := &types.AuditRecord{}
+		if json.Unmarshal(scanner.Bytes(), record) != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+
+	return records, scanner.Err()
+}
+
+func (s *Audit) match(record *types.AuditRecord, filter *types.AuditFilter) bool {
+	if len(filter.Account) > 0 && !strings.EqualFold(record.Account, filter.Account) {
+		return false
+	}
+	if len(filter.Action) > 0 {
+		if strings.HasSuffix(filter.Action, ".") {
+			if !strings.HasPrefix(record.Action, filter.Action) {
+				return false
+			}
+		} else if record.Action != filter.Action {
+			return false
+		}
+	}
+	if len(filter.IP) > 0 && record.IP != filter.IP {
+		return false
+	}
+	if len(filter.Keyword) > 0 {
+		keyword := strings.ToLower(filter.Keyword)
+		if !strings.Contains(strings.ToLower(record.Target), keyword) && !strings.Contains(strings.ToLower(record.Path), keyword) {
+			return false
+		}
+	}
+	if filter.Start != nil && time.Time(record.Time).Before(time.Time(*filter.Start)) {
+		return false
+	}
+	if filter.End != nil && time.Time(record.Time).After(time.Time(*filter.End)) {
+		return false
+	}
+
+	return true
+}
+
+// 读取输入内容的开头部分(不包括上传文件)并还原请求体
+func (s *Audit) peekInput(r *http.Request) []byte {
+	if r.Body == nil {
+		return nil
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+		return nil
+	}
+
+	head, _ := ioutil.ReadAll(io.LimitReader(r.Body, auditTargetMaxSize))
+	r.Body = &struct {
+		io.Reader
+		io.Closer
+	}{
+		Reader: io.MultiReader(bytes.NewReader(head), r.Body),
+		Closer: r.Body,
+	}
+
+	return head
+}
+
+func (s *Audit) target(r *http.Request, a types.Assistant, input []byte) string {
+	if v, ok := a.Get(types.AuditKeyTarget); ok {
+		return fmt.Sprint(v)
+	}
+
+	if r.MultipartForm != nil {
+		names := make([]string, 0)
+		for _, headers := range r.MultipartForm.File {
+			for _, header := range headers {
+				names = append(names, header.Filename)
+			}
+		}
+		sort.Strings(names)
+		return strings.Join(names, ", ")
+	}
+
+	if len(input) > 0 {
+		buf := &bytes.Buffer{}
+		if json.Compact(buf, input) == nil {
+			return buf.String()
+		}
+		return string(input)
+	}
+
+	return ""
+}
+
+func (s *Audit) outputError(output []byte) string {
+	result := &types.Result{}
+	if json.Unmarshal(output, result) != nil {
+		return ""
+	}
+	if len(result.Error.Detail) > 0 {
+		return fmt.Sprintf("%s: %s", result.Error.Summary, result.Error.Detail)
+	}
+
+	return result.Error.Summary
+}
@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"github.com/csby/wsf/server/configure"
+	"github.com/csby/wsf/types"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+type auditAssistant struct {
+	types.Assistant
+
+	keys   map[string]interface{}
+	output []byte
+}
+
+func (s *auditAssistant) EnterTime() time.Time {
+	return time.Now()
+}
+
+func (s *auditAssistant) RID() uint64 {
+	return 1
+}
+
+func (s *auditAssistant) RIP() string {
+	return "192.168.1.8"
+}
+
+func (s *auditAssistant) Get(key string) (interface{}, bool) {
+	v, ok := s.keys[key]
+	return v, ok
+}
+
+func (s *auditAssistant) IsError() bool {
+	return len(s.output) > 0
+}
+
+func (s *auditAssistant) GetOutput() []byte {
+	return s.output
+}
+
+func TestAudit_Record(t *testing.T) {
+	folder, err := ioutil.TempDir("", "wsf-audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+
+	cfg := &configure.Configure{}
+	cfg.Operation.Audit.Folder = folder
+	audit := NewAudit(nil, cfg)
+	defer audit.file.Close()
+
+	body := ""
+	handle := audit.Record("site.root.delete")(func(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+		data, _ := ioutil.ReadAll(r.Body)
+		body = string(data)
+	})
+	r := httptest.NewRequest("POST", "/opt.api/site/root/file/delete", strings.NewReader(`{ "name": "test.txt" }`))
+	handle(httptest.NewRecorder(), r, nil, &auditAssistant{keys: map[string]interface{}{types.RouterKeyAccount: "admin"}})
+	if body != `{ "name": "test.txt" }` {
+		t.Fatal("request body not restored:", body)
+	}
+
+	handle = audit.Record("service.restart")(func(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {})
+	r = httptest.NewRequest("POST", "/opt.api/service/restart", nil)
+	handle(httptest.NewRecorder(), r, nil, &auditAssistant{
+		keys:   map[string]interface{}{types.AuditKeyTarget: "server"},
+		output: []byte(`{"code":4,"error":{"summary":"不支持","detail":"restart not supported"}}`),
+	})
+
+	records, err := audit.query(&types.AuditFilter{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatal("expect 2 records, but got", len(records))
+	}
+
+	records, err = audit.query(&types.AuditFilter{Action: "site.", Account: "ADMIN", Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Target != `{"name":"test.txt"}` || !records[0].Success || records[0].IP != "192.168.1.8" {
+		t.Fatal("invalid record:", records)
+	}
+
+	records, err = audit.query(&types.AuditFilter{Keyword: "SERVER", Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Success || records[0].Error != "不支持: restart not supported" {
+		t.Fatal("invalid record:", records)
+	}
+}
@@ -1,6 +1,12 @@
 package controller
 
 import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"fmt"
+	"github.com/csby/security/certificate"
 	"github.com/csby/wsf/server/configure"
 	"github.com/csby/wsf/types"
 	"github.com/mojocn/base64Captcha"
@@ -9,11 +15,20 @@ import (
 	"time"
 )
 
+const (
+	tokenRsaKeyMinLength = 2048
+	// 签名凭证未配置过期时间时的默认值, 单位分钟, 签名凭证不支持永不过期
+	signedTokenDefaultExpiration = 30
+)
+
 type Auth struct {
 	controller
 
-	errorCount map[string]int
-	ldap       Ldap
+	limiter *limiter
+	ldap    Ldap
+	token   configure.Token
+	rsaKey  *certificate.RSAPrivate
+	revoked *revokedTokens
 }
 
 func NewAuth(log types.Log, cfg *configure.Configure, db types.TokenDatabase, chs types.SocketChannelCollection) *Auth {
@@ -22,14 +37,24 @@ func NewAuth(log types.Log, cfg *configure.Configure, db types.TokenDatabase, ch
 	instance.cfg = cfg
 	instance.dbToken = db
 	instance.wsChannels = chs
-	instance.errorCount = make(map[string]int)
 
 	if cfg != nil {
+		instance.limiter = newLimiter(&cfg.Operation.Lockout)
+		err := instance.limiter.Load()
+		if err != nil {
+			instance.LogError("load login lockout file '", cfg.Operation.Lockout.File, "' error: ", err)
+		}
 		instance.ldap.Enable = cfg.Operation.Ldap.Enable
 		instance.ldap.Host = cfg.Operation.Ldap.Host
 		instance.ldap.Port = cfg.Operation.Ldap.Port
 		instance.ldap.Base = cfg.Operation.Ldap.Base
+		instance.ldap.Roles = cfg.Operation.Ldap.Roles
+		instance.ldap.DefaultRoles = cfg.Operation.Ldap.DefaultRoles
+		instance.token = cfg.Operation.Api.Token
+	} else {
+		instance.limiter = newLimiter(nil)
 	}
+	instance.initSignedToken()
 
 	if chs != nil {
 		chs.AddFilter(instance.onWebsocketWriteFilter)
@@ -70,7 +95,7 @@ func (s *Auth) GetCaptcha(w http.ResponseWriter, r *http.Request, p types.Params
 	data := &types.Captcha{
 		ID:           captchaId,
 		Value:        base64Captcha.CaptchaWriteToBase64Encoding(captchaValue),
-		Required:     s.captchaRequired(a.RIP()),
+		Required:     s.limiter.CaptchaRequired(a.RIP()),
 		RsaPublicKey: a.RSAPublicKey(),
 	}
 
@@ -104,7 +129,13 @@ func (s *Auth) Login(w http.ResponseWriter, r *http.Request, p types.Params, a t
 		return
 	}
 
-	requireCaptcha := s.captchaRequired(a.RIP())
+	lock := s.limiter.Locked(a.RIP(), filter.Account)
+	if lock != nil {
+		a.Error(types.ErrLoginLocked, fmt.Sprintf("解锁时间: %s", lock.UnlockTime.String()))
+		return
+	}
+
+	requireCaptcha := s.limiter.CaptchaRequired(a.RIP())
 	err = filter.Check(requireCaptcha)
 	if err != nil {
 		a.Error(types.ErrInputInvalid, err)
@@ -114,6 +145,8 @@ func (s *Auth) Login(w http.ResponseWriter, r *http.Request, p types.Params, a t
 	if requireCaptcha {
 		if !base64Captcha.VerifyCaptcha(filter.CaptchaId, filter.CaptchaValue) {
 			a.Error(types.ErrLoginCaptchaInvalid)
+			// 账号失败次数仅在验证密码时记录
+			s.loginFailed(a, "")
 			return
 		}
 	}
@@ -123,7 +156,7 @@ func (s *Auth) Login(w http.ResponseWriter, r *http.Request, p types.Params, a t
 		decryptedPwd, err := a.RSADecrypt(pwd)
 		if err != nil {
 			a.Error(types.ErrLoginPasswordInvalid, err)
-			s.increaseErrorCount(a.RIP())
+			s.loginFailed(a, "")
 			return
 		}
 		pwd = string(decryptedPwd)
@@ -132,12 +165,10 @@ func (s *Auth) Login(w http.ResponseWriter, r *http.Request, p types.Params, a t
 	login, be, err := s.Authenticate(a, filter.Account, pwd)
 	if be != nil {
 		a.Error(be, err)
-		s.increaseErrorCount(a.RIP())
 		return
 	}
 
 	a.Success(login)
-	s.clearErrorCount(a.RIP())
 }
 
 func (s *Auth) LoginDoc(doc types.Doc, method string, path types.HttpPath) {
@@ -158,6 +189,68 @@ func (s *Auth) LoginDoc(doc types.Doc, method string, path types.HttpPath) {
 	function.AddOutputError(types.ErrInputInvalid)
 	function.AddOutputError(types.ErrLoginCaptchaInvalid)
 	function.AddOutputError(types.ErrLoginPasswordInvalid)
+	function.AddOutputError(types.ErrLoginLocked)
+}
+
+func (s *Auth) GetLoginLocks(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+	a.Success(s.limiter.List())
+}
+
+func (s *Auth) GetLoginLocksDoc(doc types.Doc, method string, path types.HttpPath) {
+	now := time.Now()
+	catalog := s.createCatalog(doc, "权限管理")
+	function := catalog.AddFunction(method, path, "获取登陆锁定列表")
+	function.SetNote("获取当前因登陆失败次数过多而被锁定的IP及账号")
+	function.SetOutputDataExample([]*types.LoginLock{
+		{
+			Type:       types.LoginLockTypeIP,
+			Value:      "192.168.1.8",
+			Failures:   5,
+			LockTime:   types.DateTime(now),
+			UnlockTime: types.DateTime(now.Add(30 * time.Minute)),
+		},
+	})
+	function.SetInputContentType("")
+	function.AddOutputError(types.ErrTokenInvalid)
+}
+
+func (s *Auth) ClearLoginLocks(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+	filter := &types.LoginLockFilter{}
+	err := a.GetJson(filter)
+	if err != nil {
+		a.Error(types.ErrInput, err)
+		return
+	}
+	if len(filter.Type) > 0 &&
+		filter.Type != types.LoginLockTypeIP &&
+		filter.Type != types.LoginLockTypeAccount {
+		a.Error(types.ErrInputInvalid, fmt.Sprintf("类型(%s)无效", filter.Type))
+		return
+	}
+
+	count := s.limiter.Clear(filter.Type, filter.Value)
+	account := ""
+	token := s.getToken(a.Token())
+	if token != nil {
+		account = token.UserAccount
+	}
+	s.LogInfo("login lock cleared by '", account, "': type=", filter.Type, ", value=", filter.Value, ", count=", count)
+
+	a.Success(count)
+}
+
+func (s *Auth) ClearLoginLocksDoc(doc types.Doc, method string, path types.HttpPath) {
+	catalog := s.createCatalog(doc, "权限管理")
+	function := catalog.AddFunction(method, path, "解除登陆锁定")
+	function.SetNote("解除IP或账号的登陆锁定并清除失败记录, 成功时返回解除的数量")
+	function.SetInputExample(&types.LoginLockFilter{
+		Type:  types.LoginLockTypeIP,
+		Value: "192.168.1.8",
+	})
+	function.SetOutputDataExample(1)
+	function.AddOutputError(types.ErrInput)
+	function.AddOutputError(types.ErrInputInvalid)
+	function.AddOutputError(types.ErrTokenInvalid)
 }
 
 func (s *Auth) Logout(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
@@ -173,8 +266,18 @@ func (s *Auth) Logout(w http.ResponseWriter, r *http.Request, p types.Params, a
 	}
 
 	s.writeWebSocketMessage(a.Token(), types.WSOptUserLogout, nil)
-	ok = s.dbToken.Del(tv)
-	if ok {
+	s.dbToken.Del(tv)
+	if s.token.Mode != types.TokenModeOpaque {
+		// 签名凭证在过期前依然有效, 记录已注销的凭证标识
+		claims, err := types.ParseSignedToken(tv, func(algorithm string, data, signature []byte) error {
+			return s.verifySignedToken(a, algorithm, data, signature)
+		})
+		if err == nil {
+			err = s.revoked.Revoke(claims.ID, claims.ExpiresAt)
+		}
+		if err != nil {
+			s.LogError("revoke token error: ", err)
+		}
 	}
 
 	a.Success(nil)
@@ -199,6 +302,7 @@ func (s *Auth) GetLoginAccount(w http.ResponseWriter, r *http.Request, p types.P
 		Account:   token.UserAccount,
 		Name:      token.UserName,
 		LoginTime: types.DateTime(token.LoginTime),
+		Roles:     token.Roles,
 	})
 }
 
@@ -210,6 +314,7 @@ func (s *Auth) GetLoginAccountDoc(doc types.Doc, method string, path types.HttpP
 		Account:   "admin",
 		Name:      "管理员",
 		LoginTime: types.DateTime(time.Now()),
+		Roles:     []string{types.RoleAdmin},
 	})
 	function.SetInputContentType("")
 	function.AddOutputError(types.ErrInternal)
@@ -243,6 +348,11 @@ func (s *Auth) GetOnlineUsersDoc(doc types.Doc, method string, path types.HttpPa
 }
 
 func (s *Auth) Authenticate(a types.Assistant, account, password string) (*types.Login, types.ErrorCode, error) {
+	lock := s.limiter.Locked(a.RIP(), account)
+	if lock != nil {
+		return nil, types.ErrLoginLocked, fmt.Errorf("解锁时间: %s", lock.UnlockTime.String())
+	}
+
 	act := strings.ToLower(account)
 	pwd := password
 
@@ -256,18 +366,27 @@ func (s *Auth) Authenticate(a types.Assistant, account, password string) (*types
 	}
 
 	var err error = nil
+	var roles []string = nil
 	userName := account
 	if user != nil {
-		if pwd != user.Password {
+		if !types.VerifyPassword(user.Password, pwd) {
+			s.loginFailed(a, account)
 			return nil, types.ErrLoginPasswordInvalid, nil
 		}
+		roles = user.Roles
+		if len(roles) < 1 {
+			roles = []string{types.RoleAdmin}
+		}
 	} else {
 		if s.ldap.Enable {
-			err = s.ldap.Authenticate(account, password)
+			roles, err = s.ldap.Authenticate(account, password)
 			if err != nil {
+				s.loginFailed(a, account)
 				return nil, types.ErrLoginAccountOrPasswordInvalid, err
 			}
 		} else {
+			// 不存在的账号不记录账号失败次数, 仅记录IP
+			s.loginFailed(a, "")
 			return nil, types.ErrLoginAccountNotExit, nil
 		}
 	}
@@ -281,8 +400,16 @@ func (s *Auth) Authenticate(a types.Assistant, account, password string) (*types
 		LoginTime:   now,
 		ActiveTime:  now,
 		Usage:       0,
+		Roles:       roles,
+	}
+	if s.token.Mode != types.TokenModeOpaque {
+		token.ID, err = s.newSignedToken(a, token)
+		if err != nil {
+			return nil, types.ErrInternal, err
+		}
 	}
 	s.dbToken.Set(token.ID, token)
+	s.limiter.Succeed(a.RIP(), account)
 
 	login := &types.Login{
 		Token:   token.ID,
@@ -299,6 +426,9 @@ func (s *Auth) CheckToken(w http.ResponseWriter, r *http.Request, p types.Params
 		a.Error(types.ErrTokenEmpty)
 		return true
 	}
+	if s.token.Mode != types.TokenModeOpaque {
+		return s.checkSignedToken(tokenValue, a)
+	}
 
 	token, ok := s.dbToken.Get(tokenValue, true)
 	if !ok {
@@ -316,6 +446,26 @@ func (s *Auth) CheckToken(w http.ResponseWriter, r *http.Request, p types.Params
 		a.Error(types.ErrTokenIllegal, "IP不匹配")
 		return true
 	}
+	a.Set(types.RouterKeyAccount, tokenModel.UserAccount)
+
+	return s.checkPermission(tokenModel.Roles, a)
+}
+
+func (s *Auth) checkPermission(roles []string, a types.Assistant) bool {
+	v, ok := a.Get(types.RouterKeyHttpPath)
+	if !ok {
+		return false
+	}
+	httpPath, ok := v.(types.HttpPath)
+	if !ok || httpPath == nil {
+		return false
+	}
+
+	permission := httpPath.Permission()
+	if !types.HasPermission(roles, permission) {
+		a.Error(types.ErrForbidden, fmt.Sprintf("需要角色: %s", permission))
+		return true
+	}
 
 	return false
 }
@@ -341,6 +491,148 @@ func (s *Auth) CreateTokenForAccountPassword(items []types.TokenAuth, a types.As
 	return model.Token, nil, nil
 }
 
+func (s *Auth) checkSignedToken(value string, a types.Assistant) bool {
+	claims, err := types.ParseSignedToken(value, func(algorithm string, data, signature []byte) error {
+		return s.verifySignedToken(a, algorithm, data, signature)
+	})
+	if err != nil {
+		a.Error(types.ErrTokenInvalid, err)
+		return true
+	}
+	if claims.Expired(time.Now()) {
+		a.Error(types.ErrTokenInvalid, "凭证已过期")
+		return true
+	}
+	if len(claims.IP) > 0 && claims.IP != a.RIP() {
+		a.Error(types.ErrTokenIllegal, "IP不匹配")
+		return true
+	}
+	if s.revoked.Revoked(claims.ID) {
+		a.Error(types.ErrTokenInvalid, "凭证已注销")
+		return true
+	}
+
+	// 其它服务实例签发的凭证, 缓存到本地凭证数据库以便获取登录账号及推送通知
+	_, ok := s.dbToken.Get(value, true)
+	if !ok {
+		now := time.Now()
+		s.dbToken.Set(value, &types.Token{
+			ID:          value,
+			UserAccount: claims.Account,
+			UserName:    claims.Name,
+			LoginIP:     a.RIP(),
+			LoginTime:   time.Unix(claims.IssuedAt, 0),
+			ActiveTime:  now,
+			Roles:       claims.Roles,
+		})
+	}
+	a.Set(types.RouterKeyAccount, claims.Account)
+
+	return s.checkPermission(claims.Roles, a)
+}
+
+func (s *Auth) newSignedToken(a types.Assistant, token *types.Token) (string, error) {
+	claims := &types.TokenClaims{
+		ID:       a.NewGuid(),
+		Account:  token.UserAccount,
+		Name:     token.UserName,
+		IssuedAt: token.LoginTime.Unix(),
+		Roles:    token.Roles,
+	}
+	claims.ExpiresAt = token.LoginTime.Add(time.Duration(s.token.Expiration) * time.Minute).Unix()
+	if s.token.BindIP {
+		claims.IP = token.LoginIP
+	}
+
+	switch s.token.Mode {
+	case types.TokenModeHmac:
+		if len(s.token.Secret) < 1 {
+			return "", fmt.Errorf("凭证签名密钥(secret)为空")
+		}
+		return types.NewSignedToken(claims, types.TokenAlgorithmHmac, func(data []byte) ([]byte, error) {
+			return types.HmacSign([]byte(s.token.Secret), data), nil
+		})
+	case types.TokenModeRsa:
+		if s.rsaKey == nil {
+			return "", fmt.Errorf("凭证签名密钥(keyFile)无效")
+		}
+		return types.NewSignedToken(claims, types.TokenAlgorithmRsa, func(data []byte) ([]byte, error) {
+			hashed := sha256.Sum256(data)
+			return rsa.SignPKCS1v15(rand.Reader, s.rsaKey.Key(), crypto.SHA256, hashed[:])
+		})
+	default:
+		return "", fmt.Errorf("不支持的凭证模式: %s", s.token.Mode)
+	}
+}
+
+func (s *Auth) verifySignedToken(a types.Assistant, algorithm string, data, signature []byte) error {
+	switch s.token.Mode {
+	case types.TokenModeHmac:
+		if algorithm != types.TokenAlgorithmHmac {
+			return fmt.Errorf("invalid token algorithm: %s", algorithm)
+		}
+		if len(s.token.Secret) < 1 {
+			return fmt.Errorf("凭证签名密钥(secret)为空")
+		}
+		return types.HmacVerify([]byte(s.token.Secret), data, signature)
+	case types.TokenModeRsa:
+		if algorithm != types.TokenAlgorithmRsa {
+			return fmt.Errorf("invalid token algorithm: %s", algorithm)
+		}
+		if s.rsaKey == nil {
+			return fmt.Errorf("凭证签名密钥(keyFile)无效")
+		}
+		hashed := sha256.Sum256(data)
+		return rsa.VerifyPKCS1v15(&s.rsaKey.Key().PublicKey, crypto.SHA256, hashed[:], signature)
+	default:
+		return fmt.Errorf("不支持的凭证模式: %s", s.token.Mode)
+	}
+}
+
+func (s *Auth) initSignedToken() {
+	if s.token.Mode == types.TokenModeOpaque {
+		return
+	}
+
+	if s.token.Expiration < 1 {
+		s.token.Expiration = signedTokenDefaultExpiration
+		s.LogWarning("signed token can not be never expired, expiration is set to ", s.token.Expiration, " minutes")
+	}
+
+	revoked, err := newRevokedTokens(s.token.RevokedFile)
+	if err != nil {
+		s.LogError("load revoked token file '", s.token.RevokedFile, "' error: ", err)
+	}
+	s.revoked = revoked
+	if len(s.token.RevokedFile) < 1 {
+		s.LogWarning("revoked token file is not configured, logout only takes effect on this instance")
+	}
+
+	if s.token.Mode != types.TokenModeRsa {
+		return
+	}
+	key := &certificate.RSAPrivate{}
+	if len(s.token.KeyFile) > 0 {
+		err = key.FromFile(s.token.KeyFile, s.token.KeyPassword)
+		if err != nil {
+			s.LogError("load token key file '", s.token.KeyFile, "' error: ", err)
+			return
+		}
+		if key.Length() < tokenRsaKeyMinLength {
+			s.LogError("token key file '", s.token.KeyFile, "' is too short: ", key.Length(), " bits, at least ", tokenRsaKeyMinLength)
+			return
+		}
+	} else {
+		err = key.Create(tokenRsaKeyMinLength)
+		if err != nil {
+			s.LogError("create token key error: ", err)
+			return
+		}
+		s.LogWarning("token key file is not configured, tokens are invalid after restart and can not be shared between instances")
+	}
+	s.rsaKey = key
+}
+
 func (s *Auth) onWebsocketWriteFilter(message *types.SocketMessage, channel types.SocketChannel, token *types.Token) bool {
 	if message == nil {
 		return false
@@ -371,44 +663,11 @@ func (s *Auth) onWebsocketWriteFilter(message *types.SocketMessage, channel type
 	return false
 }
 
-func (s *Auth) captchaRequired(ip string) bool {
-	if s.errorCount == nil {
-		return false
-	}
-
-	count, ok := s.errorCount[ip]
-	if ok {
-		if count < 3 {
-			return false
-		} else {
-			return true
-		}
-	}
-
-	return false
-}
-
-func (s *Auth) increaseErrorCount(ip string) {
-	if s.errorCount == nil {
-		return
-	}
-
-	count := 1
-	v, ok := s.errorCount[ip]
-	if ok {
-		count += v
-	}
-
-	s.errorCount[ip] = count
-}
-
-func (s *Auth) clearErrorCount(ip string) {
-	if s.errorCount == nil {
-		return
-	}
-
-	_, ok := s.errorCount[ip]
-	if ok {
-		delete(s.errorCount, ip)
+func (s *Auth) loginFailed(a types.Assistant, account string) {
+	locks := s.limiter.Fail(a.RIP(), account)
+	for _, lock := range locks {
+		s.LogWarning("login locked: type=", lock.Type, ", value=", lock.Value,
+			", failures=", lock.Failures, ", unlock time=", lock.UnlockTime.String())
+		s.writeWebSocketMessage("", types.WSOptLoginLock, lock)
 	}
 }
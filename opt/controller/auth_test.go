@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"github.com/csby/wsf/server/configure"
+	"github.com/csby/wsf/types"
+	"testing"
+	"time"
+)
+
+func TestAuth_SignedTokenExpiration(t *testing.T) {
+	cfg := &configure.Configure{}
+	cfg.Operation.Api.Token.Mode = types.TokenModeHmac
+	cfg.Operation.Api.Token.Secret = "secret"
+	cfg.Operation.Api.Token.Expiration = 0
+	auth := NewAuth(nil, cfg, nil, nil)
+
+	now := time.Now()
+	value, err := auth.newSignedToken(&guidAssistant{}, &types.Token{UserAccount: "admin", LoginTime: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := types.ParseSignedToken(value, func(algorithm string, data, signature []byte) error {
+		return types.HmacVerify([]byte("secret"), data, signature)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiresAt := now.Add(signedTokenDefaultExpiration * time.Minute).Unix()
+	if claims.ExpiresAt != expiresAt {
+		t.Fatal("signed token should expire in default expiration, expect", expiresAt, "but got", claims.ExpiresAt)
+	}
+}
+
+type guidAssistant struct {
+	types.Assistant
+}
+
+func (s *guidAssistant) NewGuid() string {
+	return "guid"
+}
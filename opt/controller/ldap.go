@@ -2,31 +2,104 @@ package controller
 
 import (
 	"fmt"
+	"github.com/csby/wsf/server/configure"
+	"github.com/csby/wsf/types"
 	"github.com/go-ldap/ldap"
 	"strings"
 )
 
 type Ldap struct {
-	Enable bool
-	Host   string
-	Port   int
-	Base   string
+	Enable       bool
+	Host         string
+	Port         int
+	Base         string
+	Roles        []configure.LdapRole
+	DefaultRoles []string
 }
 
-func (s *Ldap) Authenticate(account, password string) error {
+// 验证账号及密码, 并返回用户所在组对应的角色
+func (s *Ldap) Authenticate(account, password string) ([]string, error) {
 	l, err := ldap.Dial("tcp", fmt.Sprintf("%s:%d", s.Host, s.Port))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer l.Close()
 
-	loginName, _ := s.getUserName(account)
+	loginName, samAccountName := s.getUserName(account)
 	err = l.Bind(loginName, password)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	if len(s.Roles) < 1 {
+		if len(s.DefaultRoles) < 1 {
+			return []string{types.RoleAdmin}, nil
+		}
+		return s.DefaultRoles, nil
+	}
+
+	groups, err := s.getGroups(l, samAccountName)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getRoles(groups), nil
+}
+
+func (s *Ldap) getGroups(l *ldap.Conn, samAccountName string) ([]string, error) {
+	request := ldap.NewSearchRequest(s.Base,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		fmt.Sprintf("(sAMAccountName=%s)", ldap.EscapeFilter(samAccountName)),
+		[]string{"memberOf"},
+		nil)
+	result, err := l.Search(request)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]string, 0)
+	for _, entry := range result.Entries {
+		groups = append(groups, entry.GetAttributeValues("memberOf")...)
+	}
+
+	return groups, nil
+}
+
+func (s *Ldap) getRoles(groups []string) []string {
+	roles := make([]string, 0)
+	for _, item := range s.Roles {
+		for _, group := range groups {
+			if s.matchGroup(group, item.Group) {
+				roles = append(roles, item.Roles...)
+				break
+			}
+		}
+	}
+
+	if len(roles) < 1 {
+		roles = append(roles, s.DefaultRoles...)
+	}
+
+	return roles
+}
+
+// dn: 如‘CN=Domain Admins,CN=Users,DC=dev,DC=com’
+// name: 用户组名称(CN)或DN
+func (s *Ldap) matchGroup(dn, name string) bool {
+	if strings.EqualFold(dn, name) {
+		return true
+	}
+
+	items := strings.SplitN(dn, ",", 2)
+	item := strings.SplitN(items[0], "=", 2)
+	if len(item) < 2 {
+		return false
+	}
+	if !strings.EqualFold(strings.TrimSpace(item[0]), "CN") {
+		return false
+	}
+
+	return strings.EqualFold(strings.TrimSpace(item[1]), name)
 }
 
 func (s *Ldap) getUserName(account string) (loginName, samAccountName string) {
@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"github.com/csby/wsf/server/configure"
+	"testing"
+)
+
+func TestLdap_GetRoles(t *testing.T) {
+	l := &Ldap{
+		Roles: []configure.LdapRole{
+			{Group: "Web Admins", Roles: []string{"site"}},
+			{Group: "CN=Ops,CN=Users,DC=dev,DC=com", Roles: []string{"service"}},
+		},
+		DefaultRoles: []string{"guest"},
+	}
+
+	roles := l.getRoles([]string{"CN=Web Admins,CN=Users,DC=dev,DC=com", "cn=ops,cn=users,dc=dev,dc=com"})
+	if len(roles) != 2 || roles[0] != "site" || roles[1] != "service" {
+		t.Fatal("expect [site service]; actual ", roles)
+	}
+
+	roles = l.getRoles([]string{"CN=Users,DC=dev,DC=com"})
+	if len(roles) != 1 || roles[0] != "guest" {
+		t.Fatal("expect [guest]; actual ", roles)
+	}
+}
@@ -0,0 +1,374 @@
+package controller
+
+import (
+	"encoding/json"
+	"github.com/csby/wsf/server/configure"
+	"github.com/csby/wsf/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	limiterDefaultWindow          = 15 * time.Minute
+	limiterDefaultMaxFailures     = 5
+	limiterDefaultCaptchaFailures = 3
+	limiterDefaultDuration        = 30 * time.Minute
+	// 账号在某IP登陆成功后, 该IP不受账号锁定限制的时长
+	limiterTrustDuration = 30 * 24 * time.Hour
+)
+
+// 登陆失败限制: 按IP及账号统计滑动时间窗口内的失败次数, 超过限制后锁定,
+// 账号锁定对该账号曾登陆成功的IP无效, 避免他人通过错误密码锁定账号
+func newLimiter(cfg *configure.Lockout) *limiter {
+	instance := &limiter{
+		window:          limiterDefaultWindow,
+		maxFailures:     limiterDefaultMaxFailures,
+		captchaFailures: limiterDefaultCaptchaFailures,
+		duration:        limiterDefaultDuration,
+		items:           make(map[string]*limiterItem),
+		trusted:         make(map[string]time.Time),
+	}
+
+	if cfg != nil {
+		instance.filePath = cfg.File
+		if cfg.Window > 0 {
+			instance.window = time.Duration(cfg.Window) * time.Minute
+		}
+		if cfg.MaxFailures != 0 {
+			instance.maxFailures = cfg.MaxFailures
+		}
+		if cfg.CaptchaFailures > 0 {
+			instance.captchaFailures = cfg.CaptchaFailures
+		}
+		if cfg.Duration > 0 {
+			instance.duration = time.Duration(cfg.Duration) * time.Minute
+		}
+	}
+
+	return instance
+}
+
+type limiterItem struct {
+	kind       string
+	value      string
+	failures   []time.Time
+	lockTime   time.Time
+	unlockTime time.Time
+}
+
+// 保存到文件的锁定状态
+type limiterState struct {
+	Items   []*limiterRecord     `json:"items"`
+	Trusted map[string]time.Time `json:"trusted"`
+}
+
+type limiterRecord struct {
+	Kind       string      `json:"kind"`
+	Value      string      `json:"value"`
+	Failures   []time.Time `json:"failures"`
+	LockTime   time.Time   `json:"lockTime"`
+	UnlockTime time.Time   `json:"unlockTime"`
+}
+
+func (s *limiterItem) locked(now time.Time) bool {
+	return now.Before(s.unlockTime)
+}
+
+func (s *limiterItem) removeExpired(now time.Time, window time.Duration) {
+	count := len(s.failures)
+	index := 0
+	for ; index < count; index++ {
+		if now.Sub(s.failures[index]) <= window {
+			break
+		}
+	}
+	if index > 0 {
+		s.failures = s.failures[index:]
+	}
+}
+
+func (s *limiterItem) toLock() *types.LoginLock {
+	return &types.LoginLock{
+		Type:       s.kind,
+		Value:      s.value,
+		Failures:   len(s.failures),
+		LockTime:   types.DateTime(s.lockTime),
+		UnlockTime: types.DateTime(s.unlockTime),
+	}
+}
+
+type limiter struct {
+	sync.Mutex
+
+	window          time.Duration
+	maxFailures     int
+	captchaFailures int
+	duration        time.Duration
+	items           map[string]*limiterItem
+	trusted         map[string]time.Time // 账号及IP -> 最近登陆成功时间
+	filePath        string
+}
+
+// 从文件加载锁定状态, 文件路径为空或不存在时忽略
+func (s *limiter) Load() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if len(s.filePath) < 1 {
+		return nil
+	}
+	data, err := ioutil.ReadFile(s.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	state := &limiterState{}
+	err = json.Unmarshal(data, state)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range state.Items {
+		if record == nil {
+			continue
+		}
+		s.items[s.key(record.Kind, record.Value)] = &limiterItem{
+			kind:       record.Kind,
+			value:      record.Value,
+			failures:   record.Failures,
+			lockTime:   record.LockTime,
+			unlockTime: record.UnlockTime,
+		}
+	}
+	for key, value := range state.Trusted {
+		s.trusted[key] = value
+	}
+	s.removeExpired(time.Now())
+
+	return nil
+}
+
+// 返回被锁定的IP或账号, 未锁定时返回nil
+func (s *limiter) Locked(ip, account string) *types.LoginLock {
+	s.Lock()
+	defer s.Unlock()
+
+	now := time.Now()
+	item := s.get(types.LoginLockTypeIP, ip)
+	if item != nil && item.locked(now) {
+		return item.toLock()
+	}
+	if s.isTrusted(now, ip, account) {
+		return nil
+	}
+	item = s.get(types.LoginLockTypeAccount, account)
+	if item != nil && item.locked(now) {
+		return item.toLock()
+	}
+
+	return nil
+}
+
+func (s *limiter) CaptchaRequired(ip string) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	item := s.get(types.LoginLockTypeIP, ip)
+	if item == nil {
+		return false
+	}
+	item.removeExpired(time.Now(), s.window)
+
+	return len(item.failures) >= s.captchaFailures
+}
+
+// 记录登陆失败, 返回本次新触发的锁定
+func (s *limiter) Fail(ip, account string) []*types.LoginLock {
+	s.Lock()
+	defer s.Unlock()
+
+	now := time.Now()
+	s.removeExpired(now)
+
+	locks := make([]*types.LoginLock, 0)
+	lock := s.fail(now, types.LoginLockTypeIP, ip)
+	if lock != nil {
+		locks = append(locks, lock)
+	}
+	if !s.isTrusted(now, ip, account) {
+		lock = s.fail(now, types.LoginLockTypeAccount, account)
+		if lock != nil {
+			locks = append(locks, lock)
+		}
+	}
+	s.save()
+
+	return locks
+}
+
+// 登陆成功后清除失败记录(不解除其它来源的锁定)
+func (s *limiter) Succeed(ip, account string) {
+	s.Lock()
+	defer s.Unlock()
+
+	now := time.Now()
+	for _, key := range []string{s.key(types.LoginLockTypeIP, ip), s.key(types.LoginLockTypeAccount, account)} {
+		item, ok := s.items[key]
+		if ok && !item.locked(now) {
+			delete(s.items, key)
+		}
+	}
+	if len(ip) > 0 && len(account) > 0 {
+		s.trusted[s.trustKey(ip, account)] = now
+	}
+	s.save()
+}
+
+func (s *limiter) List() []*types.LoginLock {
+	s.Lock()
+	defer s.Unlock()
+
+	now := time.Now()
+	s.removeExpired(now)
+
+	locks := make([]*types.LoginLock, 0)
+	for _, item := range s.items {
+		if item.locked(now) {
+			locks = append(locks, item.toLock())
+		}
+	}
+	sort.Slice(locks, func(i, j int) bool {
+		return time.Time(locks[i].LockTime).After(time.Time(locks[j].LockTime))
+	})
+
+	return locks
+}
+
+// 解除锁定并清除失败记录, kind为空时清除全部, value为空时清除该类型的全部, 返回清除的数量
+func (s *limiter) Clear(kind, value string) int {
+	s.Lock()
+	defer s.Unlock()
+
+	count := 0
+	for key, item := range s.items {
+		if len(kind) > 0 && kind != item.kind {
+			continue
+		}
+		if len(value) > 0 && !strings.EqualFold(value, item.value) {
+			continue
+		}
+		delete(s.items, key)
+		count++
+	}
+	if count > 0 {
+		s.save()
+	}
+
+	return count
+}
+
+func (s *limiter) fail(now time.Time, kind, value string) *types.LoginLock {
+	if len(value) < 1 {
+		return nil
+	}
+
+	key := s.key(kind, value)
+	item, ok := s.items[key]
+	if !ok {
+		item = &limiterItem{kind: kind, value: value}
+		s.items[key] = item
+	}
+	if item.locked(now) {
+		return nil
+	}
+	item.failures = append(item.failures, now)
+
+	if s.maxFailures < 0 || len(item.failures) < s.maxFailures {
+		return nil
+	}
+	item.lockTime = now
+	item.unlockTime = now.Add(s.duration)
+
+	return item.toLock()
+}
+
+func (s *limiter) get(kind, value string) *limiterItem {
+	item, ok := s.items[s.key(kind, value)]
+	if !ok {
+		return nil
+	}
+
+	return item
+}
+
+func (s *limiter) key(kind, value string) string {
+	return kind + ":" + strings.ToLower(value)
+}
+
+func (s *limiter) trustKey(ip, account string) string {
+	return strings.ToLower(account) + "@" + ip
+}
+
+func (s *limiter) isTrusted(now time.Time, ip, account string) bool {
+	t, ok := s.trusted[s.trustKey(ip, account)]
+	if !ok {
+		return false
+	}
+
+	return now.Sub(t) < limiterTrustDuration
+}
+
+func (s *limiter) removeExpired(now time.Time) {
+	for key, item := range s.items {
+		item.removeExpired(now, s.window)
+		if len(item.failures) < 1 && !item.locked(now) {
+			delete(s.items, key)
+		}
+	}
+	for key, t := range s.trusted {
+		if now.Sub(t) >= limiterTrustDuration {
+			delete(s.trusted, key)
+		}
+	}
+}
+
+func (s *limiter) save() {
+	if len(s.filePath) < 1 {
+		return
+	}
+
+	state := &limiterState{
+		Items:   make([]*limiterRecord, 0, len(s.items)),
+		Trusted: s.trusted,
+	}
+	for _, item := range s.items {
+		state.Items = append(state.Items, &limiterRecord{
+			Kind:       item.kind,
+			Value:      item.value,
+			Failures:   item.failures,
+			LockTime:   item.lockTime,
+			UnlockTime: item.unlockTime,
+		})
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+	err = os.MkdirAll(filepath.Dir(s.filePath), 0700)
+	if err != nil {
+		return
+	}
+	tempPath := s.filePath + ".tmp"
+	err = ioutil.WriteFile(tempPath, data, 0600)
+	if err != nil {
+		return
+	}
+	os.Rename(tempPath, s.filePath)
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"github.com/csby/wsf/server/configure"
+	"github.com/csby/wsf/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLimiter_Fail(t *testing.T) {
+	l := newLimiter(&configure.Lockout{MaxFailures: 3, CaptchaFailures: 2})
+
+	ip := "192.168.1.8"
+	if l.CaptchaRequired(ip) {
+		t.Fatal("captcha should not be required")
+	}
+	l.Fail(ip, "admin")
+	l.Fail(ip, "test")
+	if !l.CaptchaRequired(ip) {
+		t.Fatal("captcha should be required")
+	}
+	if l.Locked(ip, "") != nil {
+		t.Fatal("should not be locked")
+	}
+
+	locks := l.Fail(ip, "admin")
+	if len(locks) != 1 || locks[0].Type != types.LoginLockTypeIP {
+		t.Fatal("expect ip lock; actual ", locks)
+	}
+	if l.Locked(ip, "") == nil {
+		t.Fatal("ip should be locked")
+	}
+	if l.Locked("192.168.1.9", "admin") != nil {
+		t.Fatal("account should not be locked")
+	}
+
+	locks = l.Fail("192.168.1.9", "ADMIN")
+	if len(locks) != 1 || locks[0].Type != types.LoginLockTypeAccount {
+		t.Fatal("expect account lock; actual ", locks)
+	}
+	if len(l.List()) != 2 {
+		t.Fatal("expect 2 locks; actual ", len(l.List()))
+	}
+
+	l.Succeed(ip, "admin")
+	if l.Locked(ip, "") == nil {
+		t.Fatal("success should not remove lock")
+	}
+
+	count := l.Clear(types.LoginLockTypeAccount, "")
+	// admin(已锁定)及test(仅有失败记录)
+	if count != 2 {
+		t.Fatal("expect 2; actual ", count)
+	}
+	if l.Locked("192.168.1.9", "admin") != nil {
+		t.Fatal("account should be unlocked")
+	}
+	l.Clear("", "")
+	if len(l.List()) != 0 {
+		t.Fatal("expect no lock")
+	}
+}
+
+func TestLimiter_Disabled(t *testing.T) {
+	l := newLimiter(&configure.Lockout{MaxFailures: -1})
+	for i := 0; i < 10; i++ {
+		if len(l.Fail("192.168.1.8", "admin")) > 0 {
+			t.Fatal("should not lock")
+		}
+	}
+	if !l.CaptchaRequired("192.168.1.8") {
+		t.Fatal("captcha should be required")
+	}
+}
+
+func TestLimiter_Trusted(t *testing.T) {
+	l := newLimiter(&configure.Lockout{MaxFailures: 2})
+
+	l.Succeed("192.168.1.8", "admin")
+	l.Fail("192.168.1.9", "admin")
+	locks := l.Fail("192.168.1.10", "admin")
+	if len(locks) != 1 || locks[0].Type != types.LoginLockTypeAccount {
+		t.Fatal("expect account lock; actual ", locks)
+	}
+	if l.Locked("192.168.1.11", "admin") == nil {
+		t.Fatal("account should be locked")
+	}
+	if l.Locked("192.168.1.8", "ADMIN") != nil {
+		t.Fatal("account should not be locked for trusted ip")
+	}
+}
+
+func TestLimiter_Load(t *testing.T) {
+	folder, err := ioutil.TempDir("", "limiter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+
+	cfg := &configure.Lockout{MaxFailures: 2, File: filepath.Join(folder, "lockout.json")}
+	l := newLimiter(cfg)
+	l.Succeed("192.168.1.8", "admin")
+	l.Fail("192.168.1.9", "admin")
+	l.Fail("192.168.1.9", "admin")
+
+	l = newLimiter(cfg)
+	err = l.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Locked("192.168.1.9", "admin") == nil {
+		t.Fatal("lock should be loaded")
+	}
+	if l.Locked("192.168.1.8", "admin") != nil {
+		t.Fatal("trusted ip should be loaded")
+	}
+}
@@ -0,0 +1,384 @@
+package controller
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"github.com/csby/wsf/logger"
+	"github.com/csby/wsf/server/configure"
+	"github.com/csby/wsf/types"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	logSearchDefaultLimit = 200
+	logSearchMaxLimit     = 2000
+	logLineMaxSize        = 1024 * 1024
+)
+
+type Log struct {
+	controller
+}
+
+func NewLog(log types.Log, cfg *configure.Configure, chs types.SocketChannelCollection) *Log {
+	instance := &Log{}
+	instance.SetLog(log)
+	instance.cfg = cfg
+	instance.wsChannels = chs
+
+	if control, ok := log.(types.LogLevelControl); ok {
+		control.AddLevelListener(instance.onLevelChanged)
+	}
+
+	return instance
+}
+
+func (s *Log) GetLevel(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+	control, ok := s.levelControl(a)
+	if !ok {
+		return
+	}
+
+	a.Success(control.GetLevel())
+}
+
+func (s *Log) GetLevelDoc(doc types.Doc, method string, path types.HttpPath) {
+	catalog := s.createCatalog(doc, "系统日志")
+	function := catalog.AddFunction(method, path, "获取日志级别")
+	function.SetNote("获取当前生效的日志级别及配置的级别")
+	function.SetInputContentType("")
+	function.SetOutputDataExample(s.levelExample())
+	function.AddOutputError(types.ErrNotSupport)
+	function.AddOutputError(types.ErrTokenInvalid)
+}
+
+func (s *Log) SetLevel(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+	control, ok := s.levelControl(a)
+	if !ok {
+		return
+	}
+	argument := &types.LogLevelArgument{}
+	err := a.GetJson(argument)
+	if err != nil {
+		a.Error(types.ErrInput, err)
+		return
+	}
+	if argument.Duration < 0 {
+		a.Error(types.ErrInputInvalid, "duration is negative")
+		return
+	}
+
+	if len(argument.Level) < 1 {
+		a.Success(control.ResetLevel())
+		return
+	}
+	level, err := control.SetLevel(argument.Level, time.Duration(argument.Duration)*time.Minute)
+	if err != nil {
+		a.Error(types.ErrInputInvalid, err)
+		return
+	}
+
+	a.Success(level)
+}
+
+func (s *Log) SetLevelDoc(doc types.Doc, method string, path types.HttpPath) {
+	catalog := s.createCatalog(doc, "系统日志")
+	function := catalog.AddFunction(method, path, "修改日志级别")
+	function.SetNote("临时修改日志级别, 到期后自动恢复为配置的级别, 修改及恢复时均输出日志并推送通知, 服务重启后恢复为配置的级别")
+	function.SetInputExample(&types.LogLevelArgument{
+		Level:    "error|warning|info|debug",
+		Duration: 10,
+	})
+	function.SetOutputDataExample(s.levelExample())
+	function.AddOutputError(types.ErrNotSupport)
+	function.AddOutputError(types.ErrInput)
+	function.AddOutputError(types.ErrInputInvalid)
+	function.AddOutputError(types.ErrTokenInvalid)
+}
+
+func (s *Log) ListFiles(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+	folder, ok := s.folder(a)
+	if !ok {
+		return
+	}
+
+	infos, err := ioutil.ReadDir(folder)
+	if err != nil && !os.IsNotExist(err) {
+		a.Error(types.ErrInternal, err)
+		return
+	}
+
+	files := make([]*types.LogFile, 0)
+	for _, info := range infos {
+		if info.IsDir() || !s.isLogFile(info.Name()) {
+			continue
+		}
+		files = append(files, &types.LogFile{
+			Name:       info.Name(),
+			Size:       info.Size(),
+			ModTime:    types.DateTime(info.ModTime()),
+			Compressed: strings.HasSuffix(info.Name(), ".gz"),
+		})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return time.Time(files[i].ModTime).After(time.Time(files[j].ModTime))
+	})
+
+	a.Success(files)
+}
+
+func (s *Log) ListFilesDoc(doc types.Doc, method string, path types.HttpPath) {
+	catalog := s.createCatalog(doc, "系统日志")
+	function := catalog.AddFunction(method, path, "获取日志文件")
+	function.SetNote("获取日志文件夹中的日志文件(包括已分割及压缩的文件), 按修改时间从新到旧排列")
+	function.SetInputContentType("")
+	function.SetOutputDataExample([]*types.LogFile{
+		{
+			Name:    "server_2020-5-1.log",
+			Size:    1024,
+			ModTime: types.DateTime(time.Now()),
+		},
+	})
+	function.AddOutputError(types.ErrNotSupport)
+	function.AddOutputError(types.ErrTokenInvalid)
+}
+
+func (s *Log) Download(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+	filePath, ok := s.filePath(a, r.FormValue("name"))
+	if !ok {
+		return
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		a.Error(types.ErrInternal, err)
+		return
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		a.Error(types.ErrInternal, err)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": info.Name()}))
+	http.ServeContent(w, r, info.Name(), info.ModTime(), file)
+}
+
+func (s *Log) DownloadDoc(doc types.Doc, method string, path types.HttpPath) {
+	catalog := s.createCatalog(doc, "系统日志")
+	function := catalog.AddFunction(method, path, "下载日志文件")
+	function.SetNote("下载指定的日志文件, 成功时返回文件内容")
+	function.SetInputContentType("")
+	function.AddInputQuery(true, "name", "文件名称", "")
+	function.AddOutputError(types.ErrNotSupport)
+	function.AddOutputError(types.ErrInput)
+	function.AddOutputError(types.ErrNotExist)
+	function.AddOutputError(types.ErrTokenInvalid)
+}
+
+func (s *Log) Search(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+	filter := &types.LogSearchFilter{}
+	err := a.GetJson(filter)
+	if err != nil {
+		a.Error(types.ErrInput, err)
+		return
+	}
+	filePath, ok := s.filePath(a, filter.Name)
+	if !ok {
+		return
+	}
+	if filter.Limit < 1 {
+		filter.Limit = logSearchDefaultLimit
+	} else if filter.Limit > logSearchMaxLimit {
+		filter.Limit = logSearchMaxLimit
+	}
+
+	result, err := s.search(filePath, filter)
+	if err != nil {
+		a.Error(types.ErrInternal, err)
+		return
+	}
+
+	a.Success(result)
+}
+
+func (s *Log) SearchDoc(doc types.Doc, method string, path types.HttpPath) {
+	catalog := s.createCatalog(doc, "系统日志")
+	function := catalog.AddFunction(method, path, "搜索日志")
+	function.SetNote("在指定的日志文件中按级别、时间范围及关键字搜索, 多行日志作为一条匹配")
+	function.SetInputExample(&types.LogSearchFilter{
+		Name:    "server_2020-5-1.log",
+		Level:   "error|warning",
+		Keyword: "timeout",
+		Limit:   200,
+	})
+	function.SetOutputDataExample(&types.LogSearchResult{
+		Total: 1,
+		Entries: []*types.LogEntry{
+			{
+				Time:    types.DateTime(time.Now()),
+				Level:   "error",
+				Caller:  "handler.go:120",
+				Message: "connect timeout",
+				Line:    10,
+			},
+		},
+	})
+	function.AddOutputError(types.ErrNotSupport)
+	function.AddOutputError(types.ErrInput)
+	function.AddOutputError(types.ErrNotExist)
+	function.AddOutputError(types.ErrTokenInvalid)
+}
+
+func (s *Log) search(filePath string, filter *types.LogSearchFilter) (*types.LogSearchResult, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(filePath, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	var levels logger.Level
+	levels.Parse(filter.Level)
+	keyword := strings.ToLower(filter.Keyword)
+	result := &types.LogSearchResult{
+		Entries: make([]*types.LogEntry, 0),
+	}
+	match := func(entry *types.LogEntry) {
+		if entry == nil {
+			return
+		}
+		if levels != 0 {
+			var level logger.Level
+			level.Parse(entry.Level)
+			if level&levels == 0 {
+				return
+			}
+		}
+		if filter.Start != nil && time.Time(entry.Time).Before(time.Time(*filter.Start)) {
+			return
+		}
+		if filter.End != nil && time.Time(entry.Time).After(time.Time(*filter.End)) {
+			return
+		}
+		if len(keyword) > 0 && !strings.Contains(strings.ToLower(entry.Message), keyword) {
+			return
+		}
+
+		result.Total++
+		if len(result.Entries) < filter.Limit {
+			result.Entries = append(result.Entries, entry)
+		}
+	}
+
+	var entry *types.LogEntry = nil
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), logLineMaxSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		next := logger.ParseLine(text)
+		if next == nil {
+			// 多行日志的后续行
+			if entry != nil {
+				entry.Message += "\n" + text
+			}
+			continue
+		}
+		match(entry)
+		entry = next
+		entry.Line = line
+	}
+	match(entry)
+
+	return result, scanner.Err()
+}
+
+func (s *Log) levelControl(a types.Assistant) (types.LogLevelControl, bool) {
+	control, ok := s.GetLog().(types.LogLevelControl)
+	if !ok {
+		a.Error(types.ErrNotSupport, "log level change is not supported")
+		return nil, false
+	}
+
+	return control, true
+}
+
+func (s *Log) levelExample() *types.LogLevel {
+	expires := types.DateTime(time.Now().Add(10 * time.Minute))
+	return &types.LogLevel{
+		Level:   "error|warning|info|debug",
+		Default: "error|warning|info",
+		Expires: &expires,
+	}
+}
+
+// 日志监听函数, 不可输出日志
+func (s *Log) onLevelChanged(level *types.LogLevel) {
+	if s.wsChannels == nil {
+		return
+	}
+
+	s.wsChannels.Write(&types.SocketMessage{
+		ID:   types.WSLogLevelChanged,
+		Data: level,
+	}, nil)
+}
+
+// 日志文件夹, 未配置时输出错误并返回false
+func (s *Log) folder(a types.Assistant) (string, bool) {
+	if s.cfg == nil || len(s.cfg.Log.Folder) < 1 {
+		a.Error(types.ErrNotSupport, "log folder is not configured")
+		return "", false
+	}
+
+	return s.cfg.Log.Folder, true
+}
+
+// 日志文件路径, 仅允许访问日志文件夹中的日志文件
+func (s *Log) filePath(a types.Assistant, name string) (string, bool) {
+	folder, ok := s.folder(a)
+	if !ok {
+		return "", false
+	}
+	if len(name) < 1 {
+		a.Error(types.ErrInput, "name is empty")
+		return "", false
+	}
+	if name != filepath.Base(name) || strings.ContainsAny(name, `/\`) || !s.isLogFile(name) {
+		a.Error(types.ErrInput, fmt.Sprintf("invalid name '%s'", name))
+		return "", false
+	}
+
+	filePath := filepath.Join(folder, name)
+	info, err := os.Stat(filePath)
+	if err != nil || info.IsDir() {
+		a.Error(types.ErrNotExist, fmt.Sprintf("file '%s' not exist", name))
+		return "", false
+	}
+
+	return filePath, true
+}
+
+func (s *Log) isLogFile(name string) bool {
+	return strings.HasSuffix(name, ".log") || strings.HasSuffix(name, ".log.gz")
+}
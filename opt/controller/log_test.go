@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"github.com/csby/wsf/server/configure"
+	"github.com/csby/wsf/types"
+	"io/ioutil"
+	"mime"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLog_Search(t *testing.T) {
+	folder, err := ioutil.TempDir("", "wsf-log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+
+	content := "2020/05/01 08:00:00 handler.go:12: info; server started\n" +
+		"2020/05/01 08:01:00 handler.go:20: error; request error: panic\n" +
+		"goroutine 1 [running]:\n" +
+		"2020/05/01 09:00:00 handler.go:20: error; connect timeout\n"
+	filePath := filepath.Join(folder, "server_2020-5-1.log")
+	err = ioutil.WriteFile(filePath, []byte(content), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Log{}
+	result, err := s.search(filePath, &types.LogSearchFilter{Level: "error", Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 2 || len(result.Entries) != 1 {
+		t.Fatal("expect 2 matched and 1 returned, but got", result.Total, len(result.Entries))
+	}
+	entry := result.Entries[0]
+	if entry.Line != 2 || entry.Message != "request error: panic\ngoroutine 1 [running]:" {
+		t.Fatal("invalid entry:", entry.Line, entry.Message)
+	}
+
+	result, err = s.search(filePath, &types.LogSearchFilter{Keyword: "RUNNING", Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 1 {
+		t.Fatal("expect 1 matched, but got", result.Total)
+	}
+}
+
+func TestLog_Download(t *testing.T) {
+	folder, err := ioutil.TempDir("", "wsf-log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+
+	name := "server; 2020-5-1.log"
+	err = ioutil.WriteFile(filepath.Join(folder, name), []byte("info"), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Log{}
+	s.cfg = &configure.Configure{}
+	s.cfg.Log.Folder = folder
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/log/file/download?name="+url.QueryEscape(name), nil)
+	s.Download(w, r, nil, nil)
+
+	disposition := w.Header().Get("Content-Disposition")
+	_, params, err := mime.ParseMediaType(disposition)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params["filename"] != name {
+		t.Fatal("invalid content disposition:", disposition)
+	}
+}
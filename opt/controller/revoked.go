@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	revokedReloadInterval = time.Second
+)
+
+// 已注销的签名凭证, 按凭证标识(jti)记录至凭证过期,
+// 配置了文件时追加保存至文件, 并在文件被其它服务实例修改后重新加载
+type revokedTokens struct {
+	sync.Mutex
+
+	filePath string
+	items    map[string]int64 // 凭证标识 -> 过期时间(unix), 0表示永不过期
+	modTime  time.Time
+	size     int64
+	checked  time.Time
+}
+
+func newRevokedTokens(filePath string) (*revokedTokens, error) {
+	instance := &revokedTokens{filePath: filePath}
+	instance.items = make(map[string]int64)
+
+	if len(filePath) > 0 {
+		err := instance.load()
+		if err != nil {
+			return instance, err
+		}
+		// 启动时清理已过期的记录
+		if instance.size > 0 {
+			err = instance.compact()
+			if err != nil {
+				return instance, err
+			}
+		}
+	}
+
+	return instance, nil
+}
+
+func (s *revokedTokens) Revoke(id string, expiresAt int64) error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.items[id] = expiresAt
+	if len(s.filePath) < 1 {
+		return nil
+	}
+
+	os.MkdirAll(filepath.Dir(s.filePath), 0777)
+	file, err := os.OpenFile(s.filePath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "%s %d\n", id, expiresAt)
+
+	return err
+}
+
+func (s *revokedTokens) Revoked(id string) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	now := time.Now()
+	if len(s.filePath) > 0 && now.Sub(s.checked) >= revokedReloadInterval {
+		s.checked = now
+		info, err := os.Stat(s.filePath)
+		if err == nil && (info.Size() != s.size || !info.ModTime().Equal(s.modTime)) {
+			s.load()
+		}
+	}
+
+	expiresAt, ok := s.items[id]
+	if !ok {
+		return false
+	}
+	if expiresAt > 0 && expiresAt < now.Unix() {
+		delete(s.items, id)
+		return false
+	}
+
+	return true
+}
+
+func (s *revokedTokens) load() error {
+	file, err := os.Open(s.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		vs := strings.Fields(scanner.Text())
+		if len(vs) != 2 {
+			continue
+		}
+		expiresAt, err := strconv.ParseInt(vs[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		if expiresAt > 0 && expiresAt < now {
+			continue
+		}
+		s.items[vs[0]] = expiresAt
+	}
+	s.modTime = info.ModTime()
+	s.size = info.Size()
+
+	return scanner.Err()
+}
+
+func (s *revokedTokens) compact() error {
+	sb := &strings.Builder{}
+	for id, expiresAt := range s.items {
+		fmt.Fprintf(sb, "%s %d\n", id, expiresAt)
+	}
+
+	tempPath := s.filePath + ".tmp"
+	err := ioutil.WriteFile(tempPath, []byte(sb.String()), 0600)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tempPath, s.filePath)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(s.filePath)
+	if err == nil {
+		s.modTime = info.ModTime()
+		s.size = info.Size()
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRevokedTokens(t *testing.T) {
+	folder, err := ioutil.TempDir("", "wsf-revoked")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+
+	filePath := filepath.Join(folder, "revoked.txt")
+	a, err := newRevokedTokens(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := newRevokedTokens(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	err = a.Revoke("t1", now.Add(time.Hour).Unix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = a.Revoke("t2", now.Add(-time.Hour).Unix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.Revoked("t1") {
+		t.Fatal("token 't1' should be revoked")
+	}
+	if a.Revoked("t2") {
+		t.Fatal("expired token 't2' should be removed")
+	}
+
+	// 其它实例重新加载文件
+	b.checked = time.Time{}
+	if !b.Revoked("t1") {
+		t.Fatal("token 't1' should be revoked in other instance")
+	}
+
+	// 重启时清理已过期的记录
+	c, err := newRevokedTokens(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.items) != 1 || !c.Revoked("t1") {
+		t.Fatal("invalid items after reload:", c.items)
+	}
+}
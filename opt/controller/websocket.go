@@ -2,6 +2,7 @@ package controller
 
 import (
 	"encoding/json"
+	"github.com/csby/wsf/logger"
 	"github.com/csby/wsf/server/configure"
 	"github.com/csby/wsf/types"
 	"github.com/gorilla/websocket"
@@ -14,6 +15,11 @@ type Websocket struct {
 	controller
 
 	wsGrader websocket.Upgrader
+
+	tailMutex sync.RWMutex
+	tails     map[types.SocketChannel]logger.Level
+
+	messages types.SocketMessageRegistry
 }
 
 func NewWebsocket(log types.Log, cfg *configure.Configure, db types.TokenDatabase, chs types.SocketChannelCollection) *Websocket {
@@ -23,10 +29,21 @@ func NewWebsocket(log types.Log, cfg *configure.Configure, db types.TokenDatabas
 	instance.dbToken = db
 	instance.wsChannels = chs
 	instance.wsGrader = websocket.Upgrader{CheckOrigin: instance.checkOrigin}
+	instance.tails = make(map[types.SocketChannel]logger.Level)
+	instance.messages = types.NewSocketMessageRegistry()
 
 	if chs != nil {
 		chs.SetListener(nil, instance.onChannelRemoved)
 		//chs.AddReader(instance.onChannelRead)
+		chs.AddReader(instance.onLogTailSubscribe)
+		if v, ok := chs.(types.SocketChannelMessages); ok {
+			instance.messages = v.Messages()
+		}
+		instance.registerMessages(instance.messages)
+
+		if tail, ok := log.(types.LogTail); ok {
+			tail.AddTailListener(instance.onLogTail)
+		}
 	}
 
 	return instance
@@ -79,6 +96,11 @@ func (s *Websocket) Notify(w http.ResponseWriter, r *http.Request, p types.Param
 				return
 			case msg, ok := <-ch.Read():
 				if !ok {
+					// channel closed by server shutdown
+					deadline := time.Now().Add(time.Second)
+					closeMessage := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutdown")
+					conn.WriteControl(websocket.CloseMessage, closeMessage, deadline)
+					conn.SetReadDeadline(deadline)
 					return
 				}
 
@@ -120,7 +142,7 @@ func (s *Websocket) Notify(w http.ResponseWriter, r *http.Request, p types.Param
 					err := json.Unmarshal(msgContent, msg)
 					if err != nil {
 						s.LogError("notify subscribe socket unmarshal read message error:", err)
-					} else {
+					} else if s.checkClientMessage(msg) {
 						s.wsChannels.Read(msg, ch)
 					}
 				}
@@ -136,11 +158,43 @@ func (s *Websocket) NotifyDoc(doc types.Doc, method string, path types.HttpPath)
 	function := catalog.AddFunction(method, path, "通知推送")
 	function.SetNote("订阅并接收系统推送的通知，该接口保持阻塞至连接关闭")
 	function.SetOutputExample(&types.SocketMessage{ID: 1})
-	function.SetInputExample(&types.SocketMessage{ID: 1})
+	s.messages.Doc(function)
 	function.SetInputContentType("")
 	function.AddOutputError(types.ErrTokenInvalid)
 }
 
+// 检查客户端发送的消息, 仅在严格模式下拒绝未注册的消息
+func (s *Websocket) checkClientMessage(msg *types.SocketMessage) bool {
+	messages := s.messages
+	err := messages.CheckClient(msg)
+	if err == nil {
+		return true
+	}
+	if messages.Strict() {
+		s.LogWarning("notify subscribe socket read message rejected:", err)
+		return false
+	}
+
+	s.LogDebug("notify subscribe socket read message unregistered:", err)
+	return true
+}
+
+func (s *Websocket) registerMessages(messages types.SocketMessageRegistry) {
+	messages.Register(types.WSOptUserLogin, "用户登陆", types.SocketDirectionServerToClient, &types.OnlineUser{})
+	messages.Register(types.WSOptUserLogout, "用户注销", types.SocketDirectionServerToClient, nil)
+	messages.Register(types.WSOptLoginLock, "登陆锁定", types.SocketDirectionServerToClient, &types.LoginLock{})
+	messages.Register(types.WSOptSiteUpload, "上传并发布后台服务管理网站", types.SocketDirectionServerToClient, &types.SiteInfo{})
+	messages.Register(types.WSDocSiteUpload, "上传并发布后台接口文档网站", types.SocketDirectionServerToClient, &types.SiteInfo{})
+	messages.Register(types.WSRootSiteUploadFile, "根站点-上传文件", types.SocketDirectionServerToClient, &types.SiteFile{})
+	messages.Register(types.WSRootSiteDeleteFile, "根站点-删除文件", types.SocketDirectionServerToClient, &types.SiteFileFilter{})
+	messages.Register(types.WSWebappSiteUpload, "上传并发布后应用网站", types.SocketDirectionServerToClient, nil)
+	messages.Register(types.WSWebappSiteDelete, "删除应用网站", types.SocketDirectionServerToClient, nil)
+	messages.Register(types.WSCustomSiteUpload, "上传并发布自定义网站", types.SocketDirectionServerToClient, &types.SiteInfo{})
+	messages.Register(types.WSLogTail, "日志实时输出", types.SocketDirectionServerToClient, &types.LogEntry{})
+	messages.Register(types.WSLogTailSubscribe, "订阅日志实时输出", types.SocketDirectionClientToServer, &types.LogTailFilter{Level: "error|warning"})
+	messages.Register(types.WSLogLevelChanged, "日志级别变更", types.SocketDirectionServerToClient, &types.LogLevel{})
+}
+
 func (s *Websocket) checkOrigin(r *http.Request) bool {
 	if r != nil {
 	}
@@ -152,6 +206,10 @@ func (s *Websocket) onChannelRemoved(channel types.SocketChannel) {
 		return
 	}
 
+	s.tailMutex.Lock()
+	delete(s.tails, channel)
+	s.tailMutex.Unlock()
+
 	token := channel.Token()
 	if token == nil {
 		return
@@ -166,6 +224,58 @@ func (s *Websocket) onChannelRemoved(channel types.SocketChannel) {
 	}
 }
 
+func (s *Websocket) onLogTailSubscribe(message *types.SocketMessage, channel types.SocketChannel) {
+	if message == nil || message.ID != types.WSLogTailSubscribe || channel == nil {
+		return
+	}
+
+	token := channel.Token()
+	if token == nil || !types.HasPermission(token.Roles, types.RoleAdmin) {
+		s.LogWarning("log tail subscribe rejected: permission denied")
+		return
+	}
+	filter := &types.LogTailFilter{}
+	err := message.GetData(filter)
+	if err != nil {
+		s.LogWarning("log tail subscribe rejected: ", err)
+		return
+	}
+
+	var level logger.Level
+	level.Parse(filter.Level)
+
+	s.tailMutex.Lock()
+	defer s.tailMutex.Unlock()
+
+	if level == 0 {
+		delete(s.tails, channel)
+	} else {
+		s.tails[channel] = level
+	}
+}
+
+// 日志监听函数, 不可输出日志
+func (s *Websocket) onLogTail(entry *types.LogEntry) {
+	s.tailMutex.RLock()
+	defer s.tailMutex.RUnlock()
+
+	if len(s.tails) < 1 {
+		return
+	}
+
+	var level logger.Level
+	level.Parse(entry.Level)
+	msg := &types.SocketMessage{
+		ID:   types.WSLogTail,
+		Data: entry,
+	}
+	for channel, levels := range s.tails {
+		if levels&level != 0 {
+			channel.Write(msg)
+		}
+	}
+}
+
 func (s *Websocket) onChannelRead(message *types.SocketMessage, channel types.SocketChannel) {
 	channel.Container().Write(&types.SocketMessage{
 		ID:   message.ID,
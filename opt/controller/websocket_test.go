@@ -0,0 +1,27 @@
+package controller
+
+import (
+	"github.com/csby/wsf/types"
+	"testing"
+)
+
+func TestWebsocket_CheckClientMessage(t *testing.T) {
+	chs := types.NewSocketChannelCollection()
+	ws := NewWebsocket(nil, nil, nil, chs)
+
+	// 未注册的消息默认交由读取函数处理
+	if !ws.checkClientMessage(&types.SocketMessage{ID: 9001}) {
+		t.Fatal("unregistered message should be accepted by default")
+	}
+	if !ws.checkClientMessage(&types.SocketMessage{ID: types.WSLogTailSubscribe}) {
+		t.Fatal("registered client message should be accepted")
+	}
+
+	ws.messages.SetStrict(true)
+	if ws.checkClientMessage(&types.SocketMessage{ID: 9001}) {
+		t.Fatal("unregistered message should be rejected in strict mode")
+	}
+	if ws.checkClientMessage(&types.SocketMessage{ID: types.WSOptUserLogin}) {
+		t.Fatal("server message should be rejected in strict mode")
+	}
+}
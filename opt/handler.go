@@ -11,6 +11,8 @@ import (
 type Handler interface {
 	Init(router types.Router, api func(path types.Path, router types.Router, tokenChecker types.RouterPreHandle) error) error
 	NotFound(w http.ResponseWriter, r *http.Request, a types.Assistant)
+	// 运维管理接口的凭证验证, 可作为接口文档调试的凭证验证(types.HttpHandlerDocumentTry), Init之前为nil
+	DocumentTryCheck() types.RouterPreHandle
 }
 
 func NewHandler(log types.Log, cfg *configure.Configure, db types.TokenDatabase, chs types.SocketChannelCollection, customSite types.Site) Handler {
@@ -40,6 +42,8 @@ type handler struct {
 	update    *controller.Update
 	site      *controller.Site
 	websocket *controller.Websocket
+	audit     *controller.Audit
+	log       *controller.Log
 }
 
 func (s *handler) Init(router types.Router, api func(path types.Path, router types.Router, tokenChecker types.RouterPreHandle) error) error {
@@ -66,6 +70,14 @@ func (s *handler) Init(router types.Router, api func(path types.Path, router typ
 	return nil
 }
 
+func (s *handler) DocumentTryCheck() types.RouterPreHandle {
+	if s.auth == nil {
+		return nil
+	}
+
+	return s.auth.CheckToken
+}
+
 func (s *handler) NotFound(w http.ResponseWriter, r *http.Request, a types.Assistant) {
 	if r.Method == "GET" {
 		http.FileServer(http.Dir(s.cfg.Root)).ServeHTTP(w, r)
@@ -81,90 +93,93 @@ func (s *handler) mapOptApi(path types.Path, router types.Router) {
 	s.update = controller.NewUpdate(s.GetLog(), s.cfg, s.svcMgr)
 	s.site = controller.NewSite(s.GetLog(), s.cfg, s.dbToken, s.wsChannels, optWebPath.Prefix, webappWebPath.Prefix, s.custom)
 	s.websocket = controller.NewWebsocket(s.GetLog(), s.cfg, s.dbToken, s.wsChannels)
+	s.audit = controller.NewAudit(s.GetLog(), s.cfg)
+	s.log = controller.NewLog(s.GetLog(), s.cfg, s.wsChannels)
 
-	tokenChecker := s.auth.CheckToken
+	anonymous := path
+	anonymous.DefaultTokenType = types.TokenTypeNone
+	public := router.PathGroup(anonymous, nil)
 	// 获取验证码
-	router.POST(path.New("/captcha").SetTokenType(types.TokenTypeNone),
-		nil, s.auth.GetCaptcha, s.auth.GetCaptchaDoc)
+	public.POST("/captcha", s.auth.GetCaptcha, s.auth.GetCaptchaDoc)
 	// 用户登陆
-	router.POST(path.New("/login").SetTokenType(types.TokenTypeNone),
-		nil, s.auth.Login, s.auth.LoginDoc)
+	public.POST("/login", s.auth.Login, s.auth.LoginDoc)
+
+	api := router.PathGroup(path, s.auth.CheckToken)
+
+	// 权限管理
+	auth := api
 	// 注销登陆
-	router.POST(path.New("/logout"),
-		tokenChecker, s.auth.Logout, s.auth.LogoutDoc)
+	auth.POST("/logout", s.auth.Logout, s.auth.LogoutDoc)
 	// 获取登录账号
-	router.POST(path.New("/login/account"),
-		tokenChecker, s.auth.GetLoginAccount, s.auth.GetLoginAccountDoc)
+	auth.POST("/login/account", s.auth.GetLoginAccount, s.auth.GetLoginAccountDoc)
 	// 获取在线用户
-	router.POST(path.New("/online/users"),
-		tokenChecker, s.auth.GetOnlineUsers, s.auth.GetOnlineUsersDoc)
+	auth.POST("/online/users", s.auth.GetOnlineUsers, s.auth.GetOnlineUsersDoc)
+	// 获取登陆锁定列表
+	auth.Require(types.RoleAdmin).POST("/login/lock/list", s.auth.GetLoginLocks, s.auth.GetLoginLocksDoc)
+	// 解除登陆锁定
+	auth.Require(types.RoleAdmin).POST("/login/lock/clear", s.auth.ClearLoginLocks, s.auth.ClearLoginLocksDoc, s.audit.Record("auth.lock.clear"))
+
+	// 操作审计
+	audit := api
+	// 查询审计记录
+	audit.Require(types.RoleAdmin).POST("/audit", s.audit.Query, s.audit.QueryDoc)
 
 	// 系统信息
-	router.POST(path.New("/monitor/host"),
-		tokenChecker, s.monitor.GetHost, s.monitor.GetHostDoc)
-	router.POST(path.New("/monitor/network/interfaces"),
-		tokenChecker, s.monitor.GetNetworkInterfaces, s.monitor.GetNetworkInterfacesDoc)
-	router.POST(path.New("/monitor/network/listen/ports"),
-		tokenChecker, s.monitor.GetNetworkListenPorts, s.monitor.GetNetworkListenPortsDoc)
+	monitor := api.Group("/monitor")
+	monitor.POST("/host", s.monitor.GetHost, s.monitor.GetHostDoc)
+	monitor.POST("/network/interfaces", s.monitor.GetNetworkInterfaces, s.monitor.GetNetworkInterfacesDoc)
+	monitor.POST("/network/listen/ports", s.monitor.GetNetworkListenPorts, s.monitor.GetNetworkListenPortsDoc)
 
 	// 后台服务
-	router.POST(path.New("/service/info"),
-		tokenChecker, s.service.Info, s.service.InfoDoc)
-	router.POST(path.New("/service/restart/enable"),
-		tokenChecker, s.service.CanRestart, s.service.CanRestartDoc)
-	router.POST(path.New("/service/restart"),
-		tokenChecker, s.service.Restart, s.service.RestartDoc)
-	router.POST(path.New("/service/update/enable"),
-		tokenChecker, s.service.CanUpdate, s.service.CanUpdateDoc)
-	router.POST(path.New("/service/update"),
-		tokenChecker, s.service.Update, s.service.UpdateDoc)
+	service := api.Group("/service")
+	service.POST("/info", s.service.Info, s.service.InfoDoc)
+	service.POST("/restart/enable", s.service.CanRestart, s.service.CanRestartDoc)
+	service.Require(types.RoleService).POST("/restart", s.service.Restart, s.service.RestartDoc, s.audit.Record("service.restart"))
+	service.POST("/update/enable", s.service.CanUpdate, s.service.CanUpdateDoc)
+	service.Require(types.RoleService).POST("/update", s.service.Update, s.service.UpdateDoc, s.audit.Record("service.update"))
 
 	// 更新管理
-	router.POST(path.New("/update/enable"),
-		tokenChecker, s.update.Enable, s.update.EnableDoc)
-	router.POST(path.New("/update/info"),
-		tokenChecker, s.update.Info, s.update.InfoDoc)
-	router.POST(path.New("/update/restart/enable"),
-		tokenChecker, s.update.CanRestart, s.update.CanRestartDoc)
-	router.POST(path.New("/update/restart"),
-		tokenChecker, s.update.Restart, s.update.RestartDoc)
-	router.POST(path.New("/update/upload/enable"),
-		tokenChecker, s.update.CanUpdate, s.update.CanUpdateDoc)
-	router.POST(path.New("/update/upload"),
-		tokenChecker, s.update.Update, s.update.UpdateDoc)
+	update := api.Group("/update")
+	update.POST("/enable", s.update.Enable, s.update.EnableDoc)
+	update.POST("/info", s.update.Info, s.update.InfoDoc)
+	update.POST("/restart/enable", s.update.CanRestart, s.update.CanRestartDoc)
+	update.Require(types.RoleService).POST("/restart", s.update.Restart, s.update.RestartDoc, s.audit.Record("update.restart"))
+	update.POST("/upload/enable", s.update.CanUpdate, s.update.CanUpdateDoc)
+	update.Require(types.RoleService).POST("/upload", s.update.Update, s.update.UpdateDoc, s.audit.Record("update.upload"))
 
 	// 网站管理
-	router.POST(path.New("/site/root/info"),
-		tokenChecker, s.site.RootInfo, s.site.RootInfoDoc)
-	router.POST(path.New("/site/root/file/upload"),
-		tokenChecker, s.site.RootUploadFile, s.site.RootUploadFileDoc)
-	router.POST(path.New("/site/root/file/delete"),
-		tokenChecker, s.site.RootDeleteFile, s.site.RootDeleteFileDoc)
-	router.POST(path.New("/site/opt/info"),
-		tokenChecker, s.site.OptInfo, s.site.OptInfoDoc)
-	router.POST(path.New("/site/opt/upload"),
-		tokenChecker, s.site.OptUpload, s.site.OptUploadDoc)
-	router.POST(path.New("/site/doc/info"),
-		tokenChecker, s.site.DocInfo, s.site.DocInfoDoc)
-	router.POST(path.New("/site/doc/upload"),
-		tokenChecker, s.site.DocUpload, s.site.DocUploadDoc)
-	router.POST(path.New("/site/custom/enable"),
-		tokenChecker, s.site.CustomEnable, s.site.CustomEnableDoc)
-	router.POST(path.New("/site/custom/info"),
-		tokenChecker, s.site.CustomInfo, s.site.CustomInfoDoc)
-	router.POST(path.New("/site/custom/upload"),
-		tokenChecker, s.site.CustomUpload, s.site.CustomUploadDoc)
-	router.POST(path.New("/site/webapp/info"),
-		tokenChecker, s.site.WebappInfo, s.site.WebappInfoDoc)
-	router.POST(path.New("/site/webapp/upload"),
-		tokenChecker, s.site.WebappUpload, s.site.WebappUploadDoc)
-	router.POST(path.New("/site/webapp/delete"),
-		tokenChecker, s.site.WebappDelete, s.site.WebappDeleteDoc)
+	site := api.Group("/site")
+	siteRoot := site.Group("/root")
+	siteRoot.POST("/info", s.site.RootInfo, s.site.RootInfoDoc)
+	siteRoot.Require(types.RoleSite).POST("/file/upload", s.site.RootUploadFile, s.site.RootUploadFileDoc, s.audit.Record("site.root.upload"))
+	siteRoot.Require(types.RoleSite).POST("/file/delete", s.site.RootDeleteFile, s.site.RootDeleteFileDoc, s.audit.Record("site.root.delete"))
+	siteOpt := site.Group("/opt")
+	siteOpt.POST("/info", s.site.OptInfo, s.site.OptInfoDoc)
+	siteOpt.Require(types.RoleSite).POST("/upload", s.site.OptUpload, s.site.OptUploadDoc, s.audit.Record("site.opt.upload"))
+	siteDoc := site.Group("/doc")
+	siteDoc.POST("/info", s.site.DocInfo, s.site.DocInfoDoc)
+	siteDoc.Require(types.RoleSite).POST("/upload", s.site.DocUpload, s.site.DocUploadDoc, s.audit.Record("site.doc.upload"))
+	site.POST("/custom/enable", s.site.CustomEnable, s.site.CustomEnableDoc)
+	site.POST("/custom/info", s.site.CustomInfo, s.site.CustomInfoDoc)
+	site.Require(types.RoleSite).POST("/custom/upload", s.site.CustomUpload, s.site.CustomUploadDoc, s.audit.Record("site.custom.upload"))
+	siteWebapp := site.Group("/webapp")
+	siteWebapp.POST("/info", s.site.WebappInfo, s.site.WebappInfoDoc)
+	siteWebapp.Require(types.RoleSite).POST("/upload", s.site.WebappUpload, s.site.WebappUploadDoc, s.audit.Record("site.webapp.upload"))
+	siteWebapp.Require(types.RoleSite).POST("/delete", s.site.WebappDelete, s.site.WebappDeleteDoc, s.audit.Record("site.webapp.delete"))
+
+	// 系统日志
+	log := api.Group("/log").Require(types.RoleAdmin)
+	log.POST("/file/list", s.log.ListFiles, s.log.ListFilesDoc)
+	log.Handle("GET", log.NewPath("/file/download").SetTokenPlace(types.TokenPlaceQuery), s.log.Download, s.log.DownloadDoc)
+	log.POST("/file/search", s.log.Search, s.log.SearchDoc)
+	log.POST("/level/get", s.log.GetLevel, s.log.GetLevelDoc)
+	log.POST("/level/set", s.log.SetLevel, s.log.SetLevelDoc, s.audit.Record("log.level.set"))
 
 	// Websocket
+	websocket := api.Group("/websocket")
 	// 通知推送
-	router.GET(path.New("/websocket/notify").SetTokenPlace(types.TokenPlaceQuery).SetWebSocket(true),
-		tokenChecker, s.websocket.Notify, s.websocket.NotifyDoc)
+	websocket.Handle("GET", websocket.NewPath("/notify").SetTokenPlace(types.TokenPlaceQuery).SetWebSocket(true),
+		s.websocket.Notify, s.websocket.NotifyDoc)
 }
 
 func (s *handler) mapOptSite(path types.Path, router types.Router, root string) {
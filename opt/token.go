@@ -0,0 +1,25 @@
+package opt
+
+import (
+	"github.com/csby/wsf/server/configure"
+	"github.com/csby/wsf/types"
+)
+
+const (
+	tokenDatabaseName = "opt"
+)
+
+// 根据配置(operation.api.token)创建凭证数据库, 配置了存储文件时凭证保存在文件中, 否则仅保存在内存中
+// 服务停止时调用Close停止后台清理并保存未保存的修改
+func NewTokenDatabase(log types.Log, cfg *configure.Configure) (types.TokenDatabaseCloser, error) {
+	if cfg == nil {
+		return types.NewMemoryTokenDatabase(0, tokenDatabaseName), nil
+	}
+
+	token := &cfg.Operation.Api.Token
+	if len(token.File) > 0 {
+		return types.NewFileTokenDatabase(log, token.Expiration, tokenDatabaseName, token.File)
+	}
+
+	return types.NewMemoryTokenDatabase(token.Expiration, tokenDatabaseName), nil
+}
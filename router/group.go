@@ -0,0 +1,84 @@
+package router
+
+import (
+	"github.com/csby/wsf/types"
+	"net/http"
+	"strings"
+)
+
+type group struct {
+	router      *Router
+	prefix      string
+	middlewares []types.RouterMiddleware
+}
+
+func (s *group) GET(httpPath types.HttpPath, preHandle types.RouterPreHandle, routerHandle types.RouterHandle, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
+	s.checkPath(httpPath)
+	s.router.GET(httpPath, preHandle, routerHandle, docHandle, appendMiddlewares(s.middlewares, middlewares...)...)
+}
+
+func (s *group) POST(httpPath types.HttpPath, preHandle types.RouterPreHandle, routerHandle types.RouterHandle, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
+	s.checkPath(httpPath)
+	s.router.POST(httpPath, preHandle, routerHandle, docHandle, appendMiddlewares(s.middlewares, middlewares...)...)
+}
+
+func (s *group) PUT(httpPath types.HttpPath, preHandle types.RouterPreHandle, routerHandle types.RouterHandle, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
+	s.checkPath(httpPath)
+	s.router.PUT(httpPath, preHandle, routerHandle, docHandle, appendMiddlewares(s.middlewares, middlewares...)...)
+}
+
+func (s *group) DELETE(httpPath types.HttpPath, preHandle types.RouterPreHandle, routerHandle types.RouterHandle, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
+	s.checkPath(httpPath)
+	s.router.DELETE(httpPath, preHandle, routerHandle, docHandle, appendMiddlewares(s.middlewares, middlewares...)...)
+}
+
+func (s *group) PATCH(httpPath types.HttpPath, preHandle types.RouterPreHandle, routerHandle types.RouterHandle, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
+	s.checkPath(httpPath)
+	s.router.PATCH(httpPath, preHandle, routerHandle, docHandle, appendMiddlewares(s.middlewares, middlewares...)...)
+}
+
+func (s *group) HEAD(httpPath types.HttpPath, preHandle types.RouterPreHandle, routerHandle types.RouterHandle, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
+	s.checkPath(httpPath)
+	s.router.HEAD(httpPath, preHandle, routerHandle, docHandle, appendMiddlewares(s.middlewares, middlewares...)...)
+}
+
+func (s *group) OPTIONS(httpPath types.HttpPath, preHandle types.RouterPreHandle, routerHandle types.RouterHandle, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
+	s.checkPath(httpPath)
+	s.router.OPTIONS(httpPath, preHandle, routerHandle, docHandle, appendMiddlewares(s.middlewares, middlewares...)...)
+}
+
+func (s *group) PathGroup(path types.Path, preHandle types.RouterPreHandle, catalogs ...string) types.RouterGroup {
+	return newPathGroup(s, path, preHandle, catalogs...)
+}
+
+func (s *group) ServeFiles(httpPath types.HttpPath, preHandle types.RouterPreHandle, root http.FileSystem, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
+	s.checkPath(httpPath)
+	s.router.ServeFiles(httpPath, preHandle, root, docHandle, appendMiddlewares(s.middlewares, middlewares...)...)
+}
+
+func (s *group) Use(middlewares ...types.RouterMiddleware) {
+	s.middlewares = appendMiddlewares(s.middlewares, middlewares...)
+}
+
+func (s *group) Group(prefix string, middlewares ...types.RouterMiddleware) types.Router {
+	return &group{
+		router:      s.router,
+		prefix:      s.prefix + prefix,
+		middlewares: appendMiddlewares(s.middlewares, middlewares...),
+	}
+}
+
+func (s *group) Document() types.Doc {
+	return s.router.Document()
+}
+
+func (s *group) checkPath(httpPath types.HttpPath) {
+	if httpPath == nil {
+		panic("http path is nil")
+	}
+
+	path := httpPath.RawPath()
+	if !strings.HasPrefix(path, s.prefix) {
+		panic("path must begin with group prefix '" + s.prefix + "' in path '" + path + "'")
+	}
+}
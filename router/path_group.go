@@ -0,0 +1,121 @@
+package router
+
+import (
+	"github.com/csby/wsf/types"
+)
+
+func newPathGroup(router types.Router, path types.Path, preHandle types.RouterPreHandle, catalogs ...string) types.RouterGroup {
+	instance := &pathGroup{
+		router:    router,
+		path:      path,
+		preHandle: preHandle,
+	}
+	instance.catalogs = append(instance.catalogs, catalogs...)
+
+	return instance
+}
+
+type pathGroup struct {
+	router    types.Router
+	path      types.Path
+	preHandle types.RouterPreHandle
+	catalogs  []string
+}
+
+func (s *pathGroup) GET(path string, routerHandle types.RouterHandle, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
+	s.Handle("GET", s.NewPath(path), routerHandle, docHandle, middlewares...)
+}
+
+func (s *pathGroup) POST(path string, routerHandle types.RouterHandle, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
+	s.Handle("POST", s.NewPath(path), routerHandle, docHandle, middlewares...)
+}
+
+func (s *pathGroup) PUT(path string, routerHandle types.RouterHandle, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
+	s.Handle("PUT", s.NewPath(path), routerHandle, docHandle, middlewares...)
+}
+
+func (s *pathGroup) DELETE(path string, routerHandle types.RouterHandle, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
+	s.Handle("DELETE", s.NewPath(path), routerHandle, docHandle, middlewares...)
+}
+
+func (s *pathGroup) PATCH(path string, routerHandle types.RouterHandle, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
+	s.Handle("PATCH", s.NewPath(path), routerHandle, docHandle, middlewares...)
+}
+
+func (s *pathGroup) HEAD(path string, routerHandle types.RouterHandle, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
+	s.Handle("HEAD", s.NewPath(path), routerHandle, docHandle, middlewares...)
+}
+
+func (s *pathGroup) OPTIONS(path string, routerHandle types.RouterHandle, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
+	s.Handle("OPTIONS", s.NewPath(path), routerHandle, docHandle, middlewares...)
+}
+
+func (s *pathGroup) Handle(method string, httpPath types.HttpPath, routerHandle types.RouterHandle, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
+	switch method {
+	case "GET":
+		s.router.GET(httpPath, s.preHandle, routerHandle, s.docHandle(docHandle), middlewares...)
+	case "POST":
+		s.router.POST(httpPath, s.preHandle, routerHandle, s.docHandle(docHandle), middlewares...)
+	case "PUT":
+		s.router.PUT(httpPath, s.preHandle, routerHandle, s.docHandle(docHandle), middlewares...)
+	case "DELETE":
+		s.router.DELETE(httpPath, s.preHandle, routerHandle, s.docHandle(docHandle), middlewares...)
+	case "PATCH":
+		s.router.PATCH(httpPath, s.preHandle, routerHandle, s.docHandle(docHandle), middlewares...)
+	case "HEAD":
+		s.router.HEAD(httpPath, s.preHandle, routerHandle, s.docHandle(docHandle), middlewares...)
+	case "OPTIONS":
+		s.router.OPTIONS(httpPath, s.preHandle, routerHandle, s.docHandle(docHandle), middlewares...)
+	default:
+		panic("unsupported method '" + method + "' in path '" + httpPath.Path() + "'")
+	}
+}
+
+func (s *pathGroup) NewPath(path string) types.HttpPath {
+	return s.path.New(path)
+}
+
+func (s *pathGroup) Group(prefix string, catalogs ...string) types.RouterGroup {
+	path := s.path
+	path.Prefix = s.path.Prefix + prefix
+
+	names := make([]string, 0, len(s.catalogs)+len(catalogs))
+	names = append(names, s.catalogs...)
+	names = append(names, catalogs...)
+
+	return newPathGroup(s.router, path, s.preHandle, names...)
+}
+
+func (s *pathGroup) Require(permission string) types.RouterGroup {
+	path := s.path
+	path.DefaultPermission = permission
+
+	return newPathGroup(s.router, path, s.preHandle, s.catalogs...)
+}
+
+func (s *pathGroup) docHandle(docHandle types.DocHandle) types.DocHandle {
+	if docHandle == nil || len(s.catalogs) < 1 {
+		return docHandle
+	}
+
+	return func(doc types.Doc, method string, path types.HttpPath) {
+		docHandle(&groupDoc{Doc: doc, catalogs: s.catalogs}, method, path)
+	}
+}
+
+// 分组文档, 创建的目录位于分组目录之下
+type groupDoc struct {
+	types.Doc
+
+	catalogs []string
+}
+
+func (s *groupDoc) AddCatalog(name string) types.Catalog {
+	catalog := s.Doc.AddCatalog(s.catalogs[0])
+	count := len(s.catalogs)
+	for i := 1; i < count; i++ {
+		catalog = catalog.AddChild(s.catalogs[i])
+	}
+
+	return catalog.AddChild(name)
+}
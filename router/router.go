@@ -1,20 +1,29 @@
 package router
 
 import (
+	"bytes"
 	"compress/flate"
 	"compress/gzip"
 	"fmt"
+	"github.com/csby/wsf/doc"
 	"github.com/csby/wsf/types"
 	"io"
+	"io/ioutil"
 	"mime"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 var _ http.Handler = New()
 
+const (
+	// 输入验证时读取的请求内容最大长度
+	validateBodyMaxSize = 8 * 1024 * 1024
+)
+
 type Router struct {
 	trees map[string]*node
 
@@ -67,6 +76,14 @@ type Router struct {
 	PanicHandler func(http.ResponseWriter, *http.Request, interface{})
 
 	Doc types.Doc
+
+	// 用于记录调用已弃用接口等警告信息, 可为nil
+	Log types.Log
+
+	middlewares []types.RouterMiddleware
+
+	// 未启用接口文档时, 用于记录接口定义(输入验证及弃用信息)
+	privateDoc types.Doc
 }
 
 func New() *Router {
@@ -82,15 +99,51 @@ func (s *Router) Document() types.Doc {
 	return s.Doc
 }
 
-func (s *Router) GET(httpPath types.HttpPath, preHandle types.RouterPreHandle, routerHandle types.RouterHandle, docHandle types.DocHandle) {
-	s.Handle("GET", httpPath, preHandle, routerHandle, docHandle)
+func (s *Router) GET(httpPath types.HttpPath, preHandle types.RouterPreHandle, routerHandle types.RouterHandle, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
+	s.Handle("GET", httpPath, preHandle, routerHandle, docHandle, middlewares...)
+}
+
+func (s *Router) POST(httpPath types.HttpPath, preHandle types.RouterPreHandle, routerHandle types.RouterHandle, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
+	s.Handle("POST", httpPath, preHandle, routerHandle, docHandle, middlewares...)
+}
+
+func (s *Router) PUT(httpPath types.HttpPath, preHandle types.RouterPreHandle, routerHandle types.RouterHandle, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
+	s.Handle("PUT", httpPath, preHandle, routerHandle, docHandle, middlewares...)
 }
 
-func (s *Router) POST(httpPath types.HttpPath, preHandle types.RouterPreHandle, routerHandle types.RouterHandle, docHandle types.DocHandle) {
-	s.Handle("POST", httpPath, preHandle, routerHandle, docHandle)
+func (s *Router) DELETE(httpPath types.HttpPath, preHandle types.RouterPreHandle, routerHandle types.RouterHandle, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
+	s.Handle("DELETE", httpPath, preHandle, routerHandle, docHandle, middlewares...)
 }
 
-func (s *Router) ServeFiles(httpPath types.HttpPath, preHandle types.RouterPreHandle, root http.FileSystem, docHandle types.DocHandle) {
+func (s *Router) PATCH(httpPath types.HttpPath, preHandle types.RouterPreHandle, routerHandle types.RouterHandle, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
+	s.Handle("PATCH", httpPath, preHandle, routerHandle, docHandle, middlewares...)
+}
+
+func (s *Router) HEAD(httpPath types.HttpPath, preHandle types.RouterPreHandle, routerHandle types.RouterHandle, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
+	s.Handle("HEAD", httpPath, preHandle, routerHandle, docHandle, middlewares...)
+}
+
+func (s *Router) OPTIONS(httpPath types.HttpPath, preHandle types.RouterPreHandle, routerHandle types.RouterHandle, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
+	s.Handle("OPTIONS", httpPath, preHandle, routerHandle, docHandle, middlewares...)
+}
+
+func (s *Router) Use(middlewares ...types.RouterMiddleware) {
+	s.middlewares = appendMiddlewares(s.middlewares, middlewares...)
+}
+
+func (s *Router) Group(prefix string, middlewares ...types.RouterMiddleware) types.Router {
+	return &group{
+		router:      s,
+		prefix:      prefix,
+		middlewares: appendMiddlewares(nil, middlewares...),
+	}
+}
+
+func (s *Router) PathGroup(path types.Path, preHandle types.RouterPreHandle, catalogs ...string) types.RouterGroup {
+	return newPathGroup(s, path, preHandle, catalogs...)
+}
+
+func (s *Router) ServeFiles(httpPath types.HttpPath, preHandle types.RouterPreHandle, root http.FileSystem, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
 	if httpPath == nil {
 		panic("http path is nil")
 	}
@@ -131,10 +184,11 @@ func (s *Router) ServeFiles(httpPath types.HttpPath, preHandle types.RouterPreHa
 			fileServer := http.FileServer(root)
 			fileServer.ServeHTTP(w, r)
 		},
-		docHandle)
+		docHandle,
+		middlewares...)
 }
 
-func (s *Router) Handle(method string, httpPath types.HttpPath, preHandle types.RouterPreHandle, routerHandle types.RouterHandle, docHandle types.DocHandle) {
+func (s *Router) Handle(method string, httpPath types.HttpPath, preHandle types.RouterPreHandle, routerHandle types.RouterHandle, docHandle types.DocHandle, middlewares ...types.RouterMiddleware) {
 	if httpPath == nil {
 		panic("http path is nil")
 	}
@@ -154,17 +208,19 @@ func (s *Router) Handle(method string, httpPath types.HttpPath, preHandle types.
 		s.trees[method] = root
 	}
 
-	// http
-	root.addRoute(path, routerHandle, preHandle)
-
 	// document
-	if docHandle != nil {
-		if s.Doc != nil {
-			if s.Doc.Enable() {
-				docHandle(s.Doc, method, httpPath)
-			}
+	handle := routerHandle
+	document := s.document(method, httpPath, docHandle)
+	if document != nil {
+		if httpPath.Validation() {
+			validator, body := document.InputValidator(method, path)
+			handle = validateHandle(validator, body, handle)
 		}
+		handle = s.deprecateHandle(document.Deprecation(method, path), handle)
 	}
+
+	// http
+	root.addRoute(path, pathHandle(httpPath, chainHandle(handle, preHandle, middlewares)), preHandle)
 }
 
 func (s *Router) Serve(w http.ResponseWriter, req *http.Request, assistant types.Assistant) {
@@ -175,11 +231,10 @@ func (s *Router) Serve(w http.ResponseWriter, req *http.Request, assistant types
 	path := req.URL.Path
 
 	if root := s.trees[req.Method]; root != nil {
-		if handle, preHandle, ps, tsr := root.getValue(path); handle != nil {
-			if preHandle != nil {
-				if preHandle(w, req, ps, assistant) {
-					return
-				}
+		if handle, _, ps, tsr := root.getValue(path); handle != nil {
+			count := len(s.middlewares)
+			for i := count - 1; i >= 0; i-- {
+				handle = s.middlewares[i](handle)
 			}
 			handle(w, req, ps, assistant)
 			return
@@ -321,3 +376,145 @@ func (s *Router) serveFilesWithGZip(w http.ResponseWriter, r *http.Request, file
 
 	return true
 }
+
+// chainHandle returns the handle that calls preHandle and routerHandle in turn,
+// wrapped by the middlewares (the first one is the outermost).
+func chainHandle(routerHandle types.RouterHandle, preHandle types.RouterPreHandle, middlewares []types.RouterMiddleware) types.RouterHandle {
+	handle := routerHandle
+	if preHandle != nil {
+		handle = func(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+			if preHandle(w, r, p, a) {
+				return
+			}
+			routerHandle(w, r, p, a)
+		}
+	}
+
+	count := len(middlewares)
+	for i := count - 1; i >= 0; i-- {
+		middleware := middlewares[i]
+		if middleware == nil {
+			continue
+		}
+		handle = middleware(handle)
+	}
+
+	return handle
+}
+
+func pathHandle(httpPath types.HttpPath, handle types.RouterHandle) types.RouterHandle {
+	return func(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+		if a != nil {
+			a.Set(types.RouterKeyHttpPath, httpPath)
+		}
+		handle(w, r, p, a)
+	}
+}
+
+// 注册接口定义, 未启用接口文档时注册到内部文档, 没有接口定义时返回nil
+func (s *Router) document(method string, httpPath types.HttpPath, docHandle types.DocHandle) types.Doc {
+	if docHandle == nil {
+		return nil
+	}
+
+	document := s.Doc
+	if document == nil || !document.Enable() {
+		if s.privateDoc == nil {
+			s.privateDoc = doc.NewDoc(true)
+		}
+		document = s.privateDoc
+	}
+	docHandle(document, method, httpPath)
+
+	return document
+}
+
+func (s *Router) deprecateHandle(deprecation *types.Deprecation, handle types.RouterHandle) types.RouterHandle {
+	if deprecation == nil {
+		return handle
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+		w.Header().Set("Deprecation", "true")
+		if deprecation.Sunset != nil {
+			w.Header().Set("Sunset", time.Time(*deprecation.Sunset).UTC().Format(http.TimeFormat))
+		}
+		if strings.HasPrefix(deprecation.Replacement, "/") || strings.HasPrefix(deprecation.Replacement, "http") {
+			w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", deprecation.Replacement))
+		}
+
+		if s.Log != nil {
+			ip := r.RemoteAddr
+			account := ""
+			if a != nil {
+				ip = a.RIP()
+				if v, ok := a.Get(types.RouterKeyAccount); ok {
+					account = fmt.Sprint(v)
+				}
+			}
+			s.Log.Warning("deprecated api called: [", r.Method, "] ", r.URL.Path,
+				", ip=", ip, ", account=", account, ", reason=", deprecation.Reason, ", replacement=", deprecation.Replacement)
+		}
+
+		handle(w, r, p, a)
+	}
+}
+
+// validateBody: 是否验证请求内容, 为false或上传文件(multipart)时不读取请求内容
+func validateHandle(validator types.InputValidator, validateBody bool, handle types.RouterHandle) types.RouterHandle {
+	if validator == nil {
+		return handle
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+		var body []byte = nil
+		if r.Body != nil && validateBody && !isMultipart(r) {
+			data, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, validateBodyMaxSize))
+			r.Body.Close()
+			if err != nil {
+				if a != nil {
+					a.Error(types.ErrInput, err)
+				} else {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+				}
+				return
+			}
+			body = data
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		errs := validator(r, body)
+		if len(errs) > 0 {
+			if a != nil {
+				a.Error(types.ErrInputInvalid, errs)
+			} else {
+				http.Error(w, errs.Error(), http.StatusBadRequest)
+			}
+			return
+		}
+
+		handle(w, r, p, a)
+	}
+}
+
+func isMultipart(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+
+	return strings.HasPrefix(mediaType, "multipart/")
+}
+
+func appendMiddlewares(items []types.RouterMiddleware, middlewares ...types.RouterMiddleware) []types.RouterMiddleware {
+	results := make([]types.RouterMiddleware, 0, len(items)+len(middlewares))
+	results = append(results, items...)
+	for _, middleware := range middlewares {
+		if middleware == nil {
+			continue
+		}
+		results = append(results, middleware)
+	}
+
+	return results
+}
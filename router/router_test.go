@@ -0,0 +1,254 @@
+package router
+
+import (
+	"encoding/json"
+	"github.com/csby/wsf/doc"
+	"github.com/csby/wsf/doc/model"
+	"github.com/csby/wsf/types"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRouter_Middleware(t *testing.T) {
+	trace := make([]string, 0)
+	middleware := func(name string) types.RouterMiddleware {
+		return func(next types.RouterHandle) types.RouterHandle {
+			return func(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+				trace = append(trace, name)
+				next(w, r, p, a)
+			}
+		}
+	}
+	preHandle := func(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) bool {
+		trace = append(trace, "pre")
+		return r.URL.Query().Get("deny") == "1"
+	}
+	handle := func(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+		trace = append(trace, "handle")
+	}
+
+	path := &types.Path{Prefix: "/api"}
+	router := New()
+	g := router.Group("/api", middleware("group"))
+	g.POST(path.New("/info"), preHandle, handle, nil, middleware("route"))
+	router.Use(middleware("global"))
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/info", nil))
+	actual := strings.Join(trace, ",")
+	if actual != "global,group,route,pre,handle" {
+		t.Fatal("expect 'global,group,route,pre,handle'; actual ", actual)
+	}
+
+	trace = trace[:0]
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/info?deny=1", nil))
+	actual = strings.Join(trace, ",")
+	if actual != "global,group,route,pre" {
+		t.Fatal("expect 'global,group,route,pre'; actual ", actual)
+	}
+}
+
+func TestRouter_GroupPrefix(t *testing.T) {
+	defer func() {
+		if err := recover(); err == nil {
+			t.Fatal("path without group prefix should panic")
+		}
+	}()
+
+	path := &types.Path{Prefix: "/doc"}
+	router := New()
+	router.Group("/api").POST(path.New("/info"), nil, nil, nil)
+}
+
+func TestRouter_Methods(t *testing.T) {
+	method := ""
+	handle := func(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+		method = r.Method
+	}
+
+	path := &types.Path{Prefix: "/api"}
+	router := New()
+	router.PUT(path.New("/item"), nil, handle, nil)
+	router.DELETE(path.New("/item"), nil, handle, nil)
+	router.PATCH(path.New("/item"), nil, handle, nil)
+	router.HEAD(path.New("/item"), nil, handle, nil)
+	router.OPTIONS(path.New("/item"), nil, handle, nil)
+
+	for _, m := range []string{"PUT", "DELETE", "PATCH", "HEAD", "OPTIONS"} {
+		method = ""
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(m, "/api/item", nil))
+		if method != m {
+			t.Fatal("expect '", m, "'; actual '", method, "'")
+		}
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/item", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatal("expect ", http.StatusMethodNotAllowed, "; actual ", w.Code)
+	}
+}
+
+func TestRouter_PathGroup(t *testing.T) {
+	handled := false
+	preHandle := func(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) bool {
+		return r.URL.Query().Get("deny") == "1"
+	}
+	handle := func(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+		handled = true
+	}
+	catalogs := make([]string, 0)
+	docHandle := func(doc types.Doc, method string, path types.HttpPath) {
+		catalogs = append(catalogs, method+" "+path.Path())
+		doc.AddCatalog("site").AddFunction(method, path, "info")
+	}
+
+	path := types.Path{Prefix: "/api", DefaultTokenType: types.TokenTypeAccountPassword}
+	router := New()
+	router.Doc = doc.NewDoc(true)
+	g := router.PathGroup(path, preHandle, "api").Group("/site", "group")
+	g.POST("/info", handle, docHandle)
+	if len(catalogs) != 1 || catalogs[0] != "POST /api/site/info" {
+		t.Fatal("expect 'POST /api/site/info'; actual ", catalogs)
+	}
+	tree := router.Doc.Catalogs().(model.CatalogSlice)
+	if len(tree) != 1 || tree[0].Name != "api" || tree[0].Children[0].Name != "group" || tree[0].Children[0].Children[0].Name != "site" {
+		t.Fatal("catalog created by doc handle should be under the group catalogs")
+	}
+	if g.NewPath("/info").TokenType() != types.TokenTypeAccountPassword {
+		t.Fatal("token type should be inherited from the group path")
+	}
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/site/info?deny=1", nil))
+	if handled {
+		t.Fatal("request should be denied by the group pre-handle")
+	}
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/site/info", nil))
+	if !handled {
+		t.Fatal("request should be handled")
+	}
+}
+
+func TestRouter_Validation(t *testing.T) {
+	type argument struct {
+		Name  string `json:"name" required:"true" note:"名称"`
+		Count int    `json:"count" note:"数量"`
+	}
+
+	handled := false
+	handle := func(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+		handled = true
+		v := &argument{}
+		err := json.NewDecoder(r.Body).Decode(v)
+		if err != nil || v.Name != "test" {
+			t.Fatal("body should be readable after validation: ", err)
+		}
+	}
+	docHandle := func(doc types.Doc, method string, path types.HttpPath) {
+		function := doc.AddCatalog("api").AddFunction(method, path, "add")
+		function.AddInputQuery(true, "kind", "类型", "", "a", "b")
+		function.SetInputExample(&argument{})
+	}
+
+	path := types.Path{Prefix: "/api", DefaultValidation: true}
+	router := New()
+	router.PathGroup(path, nil).POST("/add", handle, docHandle)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/add?kind=c", strings.NewReader(`{"count":"1"}`)))
+	if handled || w.Code != http.StatusBadRequest {
+		t.Fatal("request should be rejected")
+	}
+	body := w.Body.String()
+	for _, field := range []string{"query.kind", "body.name", "body.count"} {
+		if !strings.Contains(body, field) {
+			t.Fatal("'", field, "' not found in: ", body)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/add?kind=a", strings.NewReader(`{"name":"test","count":1}`)))
+	if !handled {
+		t.Fatal("request should be handled: ", w.Body.String())
+	}
+}
+
+func TestRouter_ValidationBody(t *testing.T) {
+	uploaded := 0
+	upload := func(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+		data, _ := ioutil.ReadAll(r.Body)
+		uploaded = len(data)
+	}
+	uploadDoc := func(doc types.Doc, method string, path types.HttpPath) {
+		function := doc.AddCatalog("api").AddFunction(method, path, "upload")
+		function.SetInputContentType("multipart/form-data")
+		function.AddInputForm(true, "file", "文件", 1, nil)
+	}
+	add := func(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+		t.Fatal("request with large body should be rejected")
+	}
+	addDoc := func(doc types.Doc, method string, path types.HttpPath) {
+		function := doc.AddCatalog("api").AddFunction(method, path, "add")
+		function.SetInputExample(&struct {
+			Name string `json:"name"`
+		}{})
+	}
+
+	path := types.Path{Prefix: "/api", DefaultValidation: true}
+	router := New()
+	group := router.PathGroup(path, nil)
+	group.POST("/upload", upload, uploadDoc)
+	group.POST("/add", add, addDoc)
+
+	// 上传文件的请求内容不读取, 由处理函数读取完整内容
+	size := validateBodyMaxSize + 1024
+	r := httptest.NewRequest("POST", "/api/upload", strings.NewReader(strings.Repeat("a", size)))
+	r.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+	router.ServeHTTP(httptest.NewRecorder(), r)
+	if uploaded != size {
+		t.Fatal("upload body should not be read by validation, read size:", uploaded)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/add", strings.NewReader(strings.Repeat(" ", size))))
+	if w.Code != http.StatusBadRequest {
+		t.Fatal("invalid status code:", w.Code)
+	}
+}
+
+func TestRouter_Deprecation(t *testing.T) {
+	handle := func(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+	}
+	docHandle := func(doc types.Doc, method string, path types.HttpPath) {
+		function := doc.AddCatalog("api").AddFunction(method, path, "old")
+		function.SetSince("1.0.0")
+		function.SetDeprecated("请使用新接口", "/api/new")
+		function.SetSunset(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC))
+	}
+
+	path := types.Path{Prefix: "/api"}
+	router := New()
+	router.PathGroup(path, nil).POST("/old", handle, docHandle)
+	router.PathGroup(path, nil).POST("/new", handle, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/old", nil))
+	if w.Header().Get("Deprecation") != "true" {
+		t.Fatal("deprecation header not set")
+	}
+	if w.Header().Get("Sunset") != "Wed, 02 Jan 2030 03:04:05 GMT" {
+		t.Fatal("invalid sunset header: ", w.Header().Get("Sunset"))
+	}
+	if !strings.Contains(w.Header().Get("Link"), "</api/new>") {
+		t.Fatal("invalid link header: ", w.Header().Get("Link"))
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/new", nil))
+	if len(w.Header().Get("Deprecation")) > 0 {
+		t.Fatal("deprecation header should not be set")
+	}
+}
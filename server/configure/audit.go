@@ -0,0 +1,7 @@
+package configure
+
+type Audit struct {
+	Folder string `json:"folder" note:"审计记录文件夹路径，空则不记录"`
+
+	Rotation
+}
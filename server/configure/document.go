@@ -1,6 +1,8 @@
 package configure
 
 type Document struct {
-	Enabled bool   `json:"enabled" note:"是否启用"`
-	Root    string `json:"root" note:"网站物理路径, 如: /home/doc"`
+	Enabled     bool   `json:"enabled" note:"是否启用"`
+	Root        string `json:"root" note:"网站物理路径, 如: /home/doc"`
+	Store       string `json:"store" note:"调试记录(历史记录及保存的请求)文件夹, 每个账号一个文件, 为空时仅保存在内存中, 如: /home/doc.store"`
+	TryInsecure bool   `json:"tryInsecure" note:"调试请求是否跳过服务端证书验证, 默认否, 使用自签名证书时可启用"`
 }
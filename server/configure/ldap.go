@@ -1,8 +1,15 @@
 package configure
 
 type Ldap struct {
-	Enable bool   `json:"enable" note:"是否启用"`
-	Host   string `json:"host" note:"主机地址"`
-	Port   int    `json:"port" note:"端口号，如389"`
-	Base   string `json:"base" note:"位置，如‘dc=dev,dc=com’"`
+	Enable       bool       `json:"enable" note:"是否启用"`
+	Host         string     `json:"host" note:"主机地址"`
+	Port         int        `json:"port" note:"端口号，如389"`
+	Base         string     `json:"base" note:"位置，如‘dc=dev,dc=com’"`
+	Roles        []LdapRole `json:"roles" note:"用户组与角色的对应关系"`
+	DefaultRoles []string   `json:"defaultRoles" note:"默认角色, 用户组没有对应角色时使用, 与roles同时为空时为管理员"`
+}
+
+type LdapRole struct {
+	Group string   `json:"group" note:"用户组名称(CN)或DN，如‘Domain Admins’"`
+	Roles []string `json:"roles" note:"角色"`
 }
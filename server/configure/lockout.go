@@ -0,0 +1,9 @@
+package configure
+
+type Lockout struct {
+	Window          int64  `json:"window" note:"失败次数统计时间窗口, 单位分钟, 默认15"`
+	MaxFailures     int    `json:"maxFailures" note:"时间窗口内同一IP或账号允许的最大失败次数, 达到后锁定, 默认5, 小于0表示不锁定"`
+	CaptchaFailures int    `json:"captchaFailures" note:"时间窗口内同一IP失败次数达到该值后需要验证码, 默认3"`
+	Duration        int64  `json:"duration" note:"锁定时长, 单位分钟, 默认30"`
+	File            string `json:"file" note:"锁定状态保存文件路径, 为空时仅保存在内存中(服务重启后锁定解除)"`
+}
@@ -3,4 +3,24 @@ package configure
 type Log struct {
 	Folder string `json:"folder" note:"文件夹路径，空则不输出到文件，输出至系统日至"`
 	Level  string `json:"level" note:"输出等级，可选值：error | warning | info | trace | debug"`
+
+	Rotation
+
+	Access AccessLog `json:"access" note:"访问日志"`
+}
+
+// 日志文件分割及清理规则
+type Rotation struct {
+	MaxSize  int  `json:"maxSize" note:"单个日志文件最大大小, 单位MB, 超过时分割, 0表示不限制"`
+	MaxAge   int  `json:"maxAge" note:"日志文件保留天数, 0表示不限制"`
+	MaxCount int  `json:"maxCount" note:"最多保留的历史日志文件数(不含当前文件), 0表示不限制"`
+	Compress bool `json:"compress" note:"是否压缩(gzip)已分割的日志文件"`
+}
+
+type AccessLog struct {
+	Enabled bool   `json:"enabled" note:"是否启用"`
+	Format  string `json:"format" note:"输出格式，可选值：common | combined | json，默认common"`
+	Folder  string `json:"folder" note:"文件夹路径，空则输出至标准输出"`
+
+	Rotation
 }
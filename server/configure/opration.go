@@ -5,4 +5,7 @@ type Operation struct {
 	Api   Api    `json:"api" note:"接口"`
 	Users []User `json:"users" note:"用户"`
 	Ldap  Ldap   `json:"ldap" note:"LDAP验证"`
+
+	Lockout Lockout `json:"lockout" note:"登陆失败锁定"`
+	Audit   Audit   `json:"audit" note:"操作审计"`
 }
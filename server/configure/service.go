@@ -3,6 +3,7 @@ package configure
 import "time"
 
 type Service struct {
-	BootTime time.Time `json:"-" note:"启动时间"`
-	Name     string    `json:"name" note:"服务名称，系统内唯一"`
+	BootTime        time.Time `json:"-" note:"启动时间"`
+	Name            string    `json:"name" note:"服务名称，系统内唯一"`
+	ShutdownTimeout int       `json:"shutdownTimeout" note:"停止服务时等待处理中请求完成的最长时间，单位秒，0表示默认(10秒)"`
 }
@@ -1,9 +1,11 @@
 package configure
 
 type Tcp struct {
-	Address     string      `json:"address" note:"监听地址，空表示监听所有地址"`
-	Port        int         `json:"port" note:"监听端口号"`
-	Enabled     bool        `json:"enabled" note:"是否启用"`
-	BehindProxy bool        `json:"behindProxy" note:"是否位于代理服务器之后"`
-	Cert        Certificate `json:"cert" note:"证书"`
+	Address           string      `json:"address" note:"监听地址，空表示监听所有地址"`
+	Port              int         `json:"port" note:"监听端口号"`
+	Enabled           bool        `json:"enabled" note:"是否启用"`
+	BehindProxy       bool        `json:"behindProxy" note:"是否位于代理服务器之后"`
+	RequestClientCert bool        `json:"requestClientCert" note:"是否要求客户端证书"`
+	MaxMessageSize    int         `json:"maxMessageSize" note:"单条消息最大字节数，0表示默认(1MB)"`
+	Cert              Certificate `json:"cert" note:"证书，服务器证书文件为空时不启用TLS"`
 }
@@ -1,5 +1,12 @@
 package configure
 
 type Token struct {
-	Expiration int64 `json:"expiration" note:"凭证过期时间, 单位分钟, 默认30, 0表示永不过期"`
+	Expiration  int64  `json:"expiration" note:"凭证过期时间, 单位分钟, 默认30, 0表示永不过期(签名凭证不支持, 使用默认值30)"`
+	File        string `json:"file" note:"凭证存储文件路径, 为空时仅保存在内存中(服务重启后凭证失效)"`
+	Mode        string `json:"mode" note:"凭证模式: 空-随机标识(保存在凭证数据库中); hmac-HMAC签名凭证; rsa-RSA签名凭证"`
+	Secret      string `json:"secret" note:"HMAC签名密钥, 多个服务实例共享凭证时须相同"`
+	KeyFile     string `json:"keyFile" note:"RSA签名私钥文件路径(PEM格式, 至少2048位), 多个服务实例共享凭证时须相同, 为空时使用服务生成的密钥(服务重启后凭证失效)"`
+	KeyPassword string `json:"keyPassword" note:"RSA签名私钥文件密码"`
+	RevokedFile string `json:"revokedFile" note:"已注销签名凭证记录文件路径, 多个服务实例共享时须指向同一文件(如共享存储), 为空时注销仅对当前服务实例有效"`
+	BindIP      bool   `json:"bindIp" note:"签名凭证是否绑定登陆IP"`
 }
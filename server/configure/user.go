@@ -1,6 +1,7 @@
 package configure
 
 type User struct {
-	Account  string `json:"account" note:"账号"`
-	Password string `json:"password" note:"密码"`
+	Account  string   `json:"account" note:"账号"`
+	Password string   `json:"password" note:"密码, 支持明文或哈希值(bcrypt、argon2id、pbkdf2-sha256、pbkdf2-sha512), 哈希值可通过参数-hash-password生成"`
+	Roles    []string `json:"roles" note:"角色, 如admin(管理员, 拥有所有权限)、site(网站管理)、service(服务管理), 为空时为管理员"`
 }
@@ -1,7 +1,10 @@
 package handler
 
 import (
+	"crypto"
 	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
 	"github.com/csby/security/certificate"
@@ -153,3 +156,21 @@ func (s *assistant) RSADecrypt(data string) (string, error) {
 
 	return string(decrypted), nil
 }
+
+func (s *assistant) RSASign(data []byte) ([]byte, error) {
+	if s.rsaPrivate == nil {
+		return nil, fmt.Errorf("rsa private key is nll")
+	}
+
+	hashed := sha256.Sum256(data)
+	return rsa.SignPKCS1v15(rand.Reader, s.rsaPrivate.Key(), crypto.SHA256, hashed[:])
+}
+
+func (s *assistant) RSAVerify(data, signature []byte) error {
+	if s.rsaPrivate == nil {
+		return fmt.Errorf("rsa private key is nll")
+	}
+
+	hashed := sha256.Sum256(data)
+	return rsa.VerifyPKCS1v15(&s.rsaPrivate.Key().PublicKey, crypto.SHA256, hashed[:], signature)
+}
@@ -115,6 +115,11 @@ func (s *httpAssistant) Error(errCode types.ErrorCode, errDetails ...interface{}
 			Detail:  fmt.Sprint(errDetails...),
 		},
 	}
+	for _, detail := range errDetails {
+		if fields, ok := detail.(types.ValidationErrors); ok {
+			result.Error.Fields = fields
+		}
+	}
 	s.outputCode = &result.Code
 
 	s.OutputJson(result)
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/csby/wsf/doc"
+	"github.com/csby/wsf/doc/generator"
+	"github.com/csby/wsf/router"
+	"github.com/csby/wsf/types"
+	"io"
+)
+
+// 导出进程内注册的接口文档, 不启动服务
+// format: markdown(md)或html
+func ExportDoc(handler types.HttpHandler, w io.Writer, format, title string) error {
+	if handler == nil {
+		return fmt.Errorf("invalid http handler: nil")
+	}
+
+	r := router.New()
+	r.Doc = doc.NewDoc(true)
+	handler.Map(r)
+
+	g := generator.NewGenerator("")
+	err := g.LoadDoc(r.Doc)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "markdown", "md", "":
+		return g.GenerateMarkdown(w, title)
+	case "html":
+		return g.GenerateHtml(w, title)
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
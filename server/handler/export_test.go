@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"bytes"
+	"github.com/csby/wsf/types"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestExportDoc(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := ExportDoc(&docHandler{}, buf, "markdown", "接口文档")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "/api/login") {
+		t.Fatal("function should be exported:", buf.String())
+	}
+
+	err = ExportDoc(&docHandler{}, buf, "pdf", "接口文档")
+	if err == nil {
+		t.Fatal("error expected for unsupported format")
+	}
+}
+
+type docHandler struct {
+}
+
+func (s *docHandler) Map(router types.Router) {
+	path := types.Path{Prefix: "/api"}
+	router.POST(path.New("/login"), nil, func(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+	}, func(doc types.Doc, method string, path types.HttpPath) {
+		catalog := doc.AddCatalog("接口")
+		catalog.AddFunction(method, path, "用户登录")
+	})
+}
+
+func (s *docHandler) PreRouting(w http.ResponseWriter, r *http.Request, a types.Assistant) bool {
+	return false
+}
+
+func (s *docHandler) PostRouting(w http.ResponseWriter, r *http.Request, a types.Assistant) {
+}
+
+func (s *docHandler) NotFound() func(http.ResponseWriter, *http.Request, types.Assistant) {
+	return nil
+}
+
+func (s *docHandler) Extend() types.HttpHandlerExtend {
+	return nil
+}
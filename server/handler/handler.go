@@ -1,21 +1,42 @@
 package handler
 
 import (
+	"context"
 	"fmt"
 	"github.com/csby/security/certificate"
 	"github.com/csby/wsf/doc"
 	"github.com/csby/wsf/doc/web"
+	"github.com/csby/wsf/logger"
 	"github.com/csby/wsf/router"
+	"github.com/csby/wsf/server/configure"
 	"github.com/csby/wsf/types"
+	"net"
 	"net/http"
+	"strings"
 )
 
-func NewHttpHandler(log types.Log, handler types.HttpHandler) (http.Handler, error) {
+const (
+	accessLogPrefix = "access"
+)
+
+type HttpHandler interface {
+	http.Handler
+
+	// 关闭所有websocket通道, 并等待处理中的请求及PostRouting完成
+	Shutdown(ctx context.Context) error
+}
+
+func NewHttpHandler(log types.Log, handler types.HttpHandler) (HttpHandler, error) {
+	return NewHttpHandlerWithConfig(log, handler, nil)
+}
+
+// cfg: 服务配置, 用于生成接口文档调试的请求地址, 为nil时不提供调试接口
+func NewHttpHandlerWithConfig(log types.Log, handler types.HttpHandler, cfg *configure.Configure) (HttpHandler, error) {
 	instance := &httpHandler{handler: handler, router: router.New()}
 	instance.SetLog(log)
 	instance.rid = &randNumber{id: 0, max: 0}
 	privateKey := &certificate.RSAPrivate{}
-	err := privateKey.Create(1024)
+	err := privateKey.Create(2048)
 	if err == nil {
 		instance.randKey = privateKey
 	}
@@ -25,17 +46,39 @@ func NewHttpHandler(log types.Log, handler types.HttpHandler) (http.Handler, err
 		redirectToHttps := false
 		documentEnabled := false
 		documentRoot := ""
+		documentStore := ""
 		extend := handler.Extend()
 		if extend != nil {
 			redirectToHttps = extend.RedirectToHttps()
 			documentEnabled = extend.DocumentEnabled()
 			documentRoot = extend.DocumentRoot()
 			server1erInfo = extend.ServerInfo()
+			if v, ok := extend.(types.HttpHandlerDocumentStore); ok {
+				documentStore = v.DocumentStore()
+			}
+			if v, ok := extend.(types.HttpHandlerSocketChannels); ok {
+				instance.socketChannels = v.SocketChannels()
+			}
+			if v, ok := extend.(types.HttpHandlerAccessLog); ok {
+				instance.accessLog = v.AccessLog()
+			}
+		}
+
+		if instance.accessLog == nil && cfg != nil {
+			accessWriter, err := logger.NewAccessWriter(&cfg.Log.Access, accessLogPrefix)
+			if err != nil {
+				return nil, err
+			}
+			if accessWriter != nil {
+				instance.accessWriter = accessWriter
+				instance.accessLog = accessWriter
+			}
 		}
 
 		instance.router.NotFound = handler.NotFound()
 		instance.redirectToHttps = redirectToHttps
 		instance.router.Doc = doc.NewDoc(documentEnabled)
+		instance.router.Log = log
 
 		instance.router.Doc.OnFunctionReady(func(index int, method, path, name string) {
 			if log != nil {
@@ -46,7 +89,22 @@ func NewHttpHandler(log types.Log, handler types.HttpHandler) (http.Handler, err
 		handler.Map(instance.router)
 
 		if documentEnabled {
+			// 凭证验证可能在Map中才初始化(如运维管理接口)
+			var documentTryCheck types.RouterPreHandle = nil
+			if v, ok := extend.(types.HttpHandlerDocumentTry); ok {
+				documentTryCheck = v.DocumentTryCheck()
+			}
 			docHandler := web.NewHandler(documentRoot, web.SitePath, web.ApiPath)
+			err = docHandler.SetStore(documentStore)
+			if err != nil && log != nil {
+				log.Warning("load document store '", documentStore, "' fail: ", err)
+			}
+			tryUrls := documentTryUrls(cfg)
+			if documentTryCheck != nil && len(tryUrls) > 0 {
+				docHandler.SetTry(tryUrls, documentTryCheck, cfg.Document.TryInsecure)
+			} else if log != nil {
+				log.Info("document try api is disabled: token check or listener not configured")
+			}
 			docHandler.Init(instance.router, server1erInfo)
 
 			if log != nil {
@@ -62,3 +120,31 @@ func NewHttpHandler(log types.Log, handler types.HttpHandler) (http.Handler, err
 
 	return instance, nil
 }
+
+// 接口文档调试的请求地址, 由配置的监听地址生成, 不使用请求中的主机名
+func documentTryUrls(cfg *configure.Configure) map[string]string {
+	urls := make(map[string]string)
+	if cfg == nil {
+		return urls
+	}
+
+	if cfg.Http.Enabled {
+		urls["http"] = fmt.Sprintf("http://%s", localHost(cfg.Http.Address, cfg.Http.Port))
+	}
+	if cfg.Https.Enabled {
+		urls["https"] = fmt.Sprintf("https://%s", localHost(cfg.Https.Address, cfg.Https.Port))
+	}
+
+	return urls
+}
+
+func localHost(address string, port int) string {
+	ip := strings.TrimSpace(address)
+	if ip == "" || ip == "0.0.0.0" {
+		ip = "127.0.0.1"
+	} else if ip == "::" || ip == "[::]" {
+		ip = "::1"
+	}
+
+	return net.JoinHostPort(strings.Trim(ip, "[]"), fmt.Sprint(port))
+}
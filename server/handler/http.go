@@ -1,13 +1,18 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/csby/security/certificate"
+	"github.com/csby/wsf/logger"
 	"github.com/csby/wsf/router"
 	"github.com/csby/wsf/types"
 	"net"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -20,10 +25,28 @@ type httpHandler struct {
 
 	rid     types.RandNumber
 	randKey *certificate.RSAPrivate
+
+	socketChannels types.SocketChannelCollection
+	waitGroup      sync.WaitGroup
+	waitMutex      sync.Mutex
+	shuttingDown   bool
+
+	accessLog    types.AccessLog
+	accessWriter *logger.AccessWriter // 根据配置创建的访问日志, 停止服务时关闭
 }
 
 func (s *httpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.beginWork() {
+		w.Header().Set("Connection", "close")
+		http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	defer s.waitGroup.Done()
+
 	r.Close = true
+	if s.accessLog != nil {
+		w = &responseWriter{ResponseWriter: w}
+	}
 	a := s.newAssistant(w, r)
 	s.LogDebug("new request: rid=", a.rid,
 		", rip=", a.rip,
@@ -37,6 +60,8 @@ func (s *httpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			if r.Method == "GET" {
 				redirectUrl := fmt.Sprintf("https://%s%s", r.Host, a.path)
 				http.Redirect(w, r, redirectUrl, http.StatusMovedPermanently)
+				a.leaveTime = time.Now()
+				s.writeAccessLog(w, r, a)
 				return
 			}
 		}
@@ -44,7 +69,14 @@ func (s *httpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	defer func(w http.ResponseWriter, r *http.Request, a *httpAssistant) {
 		a.leaveTime = time.Now()
-		go s.postRouting(w, r, a)
+		if s.beginWork() {
+			go func() {
+				defer s.waitGroup.Done()
+				s.postRouting(w, r, a)
+			}()
+		} else {
+			s.postRouting(w, r, a)
+		}
 	}(w, r, a)
 
 	defer func(a *httpAssistant) {
@@ -68,6 +100,46 @@ func (s *httpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	s.router.Serve(w, r, a)
 }
 
+func (s *httpHandler) Shutdown(ctx context.Context) error {
+	s.waitMutex.Lock()
+	s.shuttingDown = true
+	s.waitMutex.Unlock()
+
+	if s.accessWriter != nil {
+		defer s.accessWriter.Close()
+	}
+
+	if v, ok := s.socketChannels.(types.SocketChannelShutdown); ok {
+		v.Shutdown()
+	}
+
+	done := make(chan bool)
+	go func() {
+		s.waitGroup.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// 登记一项处理中的工作, 开始停止服务后返回false
+func (s *httpHandler) beginWork() bool {
+	s.waitMutex.Lock()
+	defer s.waitMutex.Unlock()
+
+	if s.shuttingDown {
+		return false
+	}
+	s.waitGroup.Add(1)
+
+	return true
+}
+
 func (s *httpHandler) preRouting(w http.ResponseWriter, r *http.Request, a *httpAssistant) bool {
 	if s.handler == nil {
 		return false
@@ -83,11 +155,70 @@ func (s *httpHandler) postRouting(w http.ResponseWriter, r *http.Request, a *htt
 		}
 	}()
 
+	s.writeAccessLog(w, r, a)
+
 	if s.handler != nil {
 		s.handler.PostRouting(w, r, a)
 	}
 }
 
+func (s *httpHandler) writeAccessLog(w http.ResponseWriter, r *http.Request, a *httpAssistant) {
+	if s.accessLog == nil {
+		return
+	}
+
+	entry := &types.AccessEntry{
+		RID:       a.rid,
+		RIP:       a.rip,
+		Time:      a.enterTime,
+		Elapsed:   a.leaveTime.Sub(a.enterTime),
+		Method:    r.Method,
+		Schema:    a.schema,
+		Host:      r.Host,
+		Uri:       accessUri(r.URL),
+		Proto:     r.Proto,
+		Status:    http.StatusOK,
+		Referer:   r.Referer(),
+		UserAgent: r.UserAgent(),
+	}
+	if v, ok := a.Get(types.RouterKeyAccount); ok {
+		entry.Account = fmt.Sprint(v)
+	}
+	if rw, ok := w.(*responseWriter); ok {
+		entry.Status = rw.Status()
+		entry.Size = rw.size
+	}
+
+	s.accessLog.Write(entry)
+}
+
+// 访问日志中的地址, 隐藏请求参数中的凭证
+func accessUri(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.RequestURI()
+	}
+
+	values, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return u.EscapedPath()
+	}
+	masked := false
+	for name := range values {
+		if strings.ToLower(name) == types.TokenName {
+			values[name] = []string{"***"}
+			masked = true
+		}
+	}
+	if !masked {
+		return u.RequestURI()
+	}
+
+	v := *u
+	v.RawQuery = values.Encode()
+
+	return v.RequestURI()
+}
+
 func (s *httpHandler) newAssistant(w http.ResponseWriter, r *http.Request) *httpAssistant {
 	instance := &httpAssistant{response: w, request: r, schema: "http"}
 	instance.method = r.Method
@@ -104,7 +235,7 @@ func (s *httpHandler) newAssistant(w http.ResponseWriter, r *http.Request) *http
 	instance.restart = s.restart()
 	instance.token = r.Header.Get("token")
 	if instance.token == "" {
-		if r.Method == "GET" {
+		if r.Method == "GET" || r.Method == "HEAD" {
 			instance.token = r.FormValue("token")
 		}
 	}
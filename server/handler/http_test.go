@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"github.com/csby/wsf/server/configure"
+	"github.com/csby/wsf/types"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestHttpHandler_Shutdown(t *testing.T) {
+	h, err := NewHttpHandler(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = h.Shutdown(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatal("request after shutdown should be rejected, but got status", w.Code)
+	}
+}
+
+func TestHttpAssistant_ErrorFields(t *testing.T) {
+	h := &httpHandler{rid: &randNumber{}}
+	w := httptest.NewRecorder()
+	a := h.newAssistant(w, httptest.NewRequest("POST", "/", nil))
+
+	errs := make(types.ValidationErrors, 0).Add(types.ValidationPlaceBody, "name", "不能为空")
+	a.Error(types.ErrInputInvalid, errs)
+
+	result := &types.Result{}
+	err := result.Unmarshal(w.Body.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Error.Fields) != 1 || result.Error.Fields[0].Field != "name" {
+		t.Fatal("invalid fields:", w.Body.String())
+	}
+	if result.Error.Detail != errs.Error() {
+		t.Fatal("invalid detail:", result.Error.Detail)
+	}
+}
+
+func TestHttpHandler_DocumentTryUrls(t *testing.T) {
+	cfg := &configure.Configure{}
+	cfg.Http.Enabled = true
+	cfg.Http.Port = 8080
+	cfg.Https.Enabled = true
+	cfg.Https.Address = "192.168.1.2"
+	cfg.Https.Port = 8443
+
+	urls := documentTryUrls(cfg)
+	if urls["http"] != "http://127.0.0.1:8080" {
+		t.Fatal("invalid http url:", urls["http"])
+	}
+	if urls["https"] != "https://192.168.1.2:8443" {
+		t.Fatal("invalid https url:", urls["https"])
+	}
+	if len(documentTryUrls(nil)) != 0 {
+		t.Fatal("empty urls expected without configure")
+	}
+}
+
+func TestHttpHandler_AccessUri(t *testing.T) {
+	u, _ := url.Parse("/opt.api/log/file/download?name=a.log&token=t1")
+	uri := accessUri(u)
+	if strings.Contains(uri, "t1") || !strings.Contains(uri, "name=a.log") {
+		t.Fatal("token should be masked:", uri)
+	}
+	u, _ = url.Parse("/opt.api/login")
+	if accessUri(u) != "/opt.api/login" {
+		t.Fatal("invalid uri:", accessUri(u))
+	}
+}
+
+func TestHttpHandler_AccessLogConfig(t *testing.T) {
+	cfg := &configure.Configure{}
+	cfg.Log.Access.Enabled = true
+	cfg.Log.Access.Format = "xml"
+	_, err := NewHttpHandlerWithConfig(nil, &docHandler{}, cfg)
+	if err == nil {
+		t.Fatal("error expected for invalid access log format")
+	}
+
+	cfg.Log.Access.Format = "json"
+	h, err := NewHttpHandlerWithConfig(nil, &docHandler{}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.(*httpHandler).accessWriter == nil {
+		t.Fatal("access log should be created from configure")
+	}
+	h.Shutdown(context.Background())
+}
@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"github.com/csby/wsf/types"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+type TcpHandler interface {
+	// conn: 已接受的连接(TLS时为*tls.Conn)
+	// remoteAddr: 客户端地址
+	ServeTCP(conn net.Conn, remoteAddr string)
+	// 关闭所有连接, 并等待处理中的连接结束
+	Close()
+}
+
+const (
+	// TLS握手超时时间
+	tcpHandshakeTimeout = 10 * time.Second
+)
+
+func NewTcpHandler(log types.Log, handler types.TcpHandler, maxMessageSize int) (TcpHandler, error) {
+	if handler == nil {
+		return nil, fmt.Errorf("invalid tcp handler: nil")
+	}
+
+	instance := &tcpHandler{handler: handler, maxMessageSize: maxMessageSize}
+	instance.SetLog(log)
+	instance.cid = &randNumber{id: 0, max: 0}
+	instance.conns = make(map[uint64]*tcpConn)
+	if instance.maxMessageSize < 1 {
+		instance.maxMessageSize = types.TcpMessageDefaultMaxSize
+	}
+
+	return instance, nil
+}
+
+type tcpHandler struct {
+	types.Base
+
+	handler        types.TcpHandler
+	maxMessageSize int
+
+	cid    types.RandNumber
+	conns  map[uint64]*tcpConn
+	mu     sync.Mutex
+	wg     sync.WaitGroup
+	closed bool
+}
+
+func (s *tcpHandler) ServeTCP(conn net.Conn, remoteAddr string) {
+	c := s.newConn(conn, remoteAddr)
+	if !s.add(c) {
+		conn.Close()
+		return
+	}
+	defer s.wg.Done()
+	defer s.remove(c)
+	defer conn.Close()
+
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		tlsConn.SetDeadline(time.Now().Add(tcpHandshakeTimeout))
+		err := tlsConn.Handshake()
+		tlsConn.SetDeadline(time.Time{})
+		if err != nil {
+			s.LogDebug("tcp connection handshake error(id=", c.id, ", rip=", c.remoteAddr, "): ", err)
+			return
+		}
+		c.tls = true
+	}
+
+	s.LogDebug("new tcp connection: id=", c.id,
+		", rip=", c.remoteAddr,
+		", tls=", c.tls)
+
+	if !s.onConnected(c) {
+		return
+	}
+	defer s.onDisconnected(c)
+
+	for {
+		message, err := c.read(s.maxMessageSize)
+		if err != nil {
+			if err != io.EOF && !c.isClosed() {
+				s.LogDebug("tcp connection read error(id=", c.id, ", rip=", c.remoteAddr, "): ", err)
+			}
+			return
+		}
+
+		s.onMessage(c, message)
+	}
+}
+
+func (s *tcpHandler) Close() {
+	s.mu.Lock()
+	s.closed = true
+	for _, c := range s.conns {
+		c.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+func (s *tcpHandler) onConnected(c *tcpConn) (ok bool) {
+	defer func() {
+		if err := recover(); err != nil {
+			s.LogError("tcp connection OnConnected error(id=", c.id, ", rip=", c.remoteAddr, "): ", err)
+			ok = false
+		}
+	}()
+
+	return s.handler.OnConnected(c)
+}
+
+func (s *tcpHandler) onMessage(c *tcpConn, message []byte) {
+	defer func() {
+		if err := recover(); err != nil {
+			s.LogError("tcp connection OnMessage error(id=", c.id, ", rip=", c.remoteAddr, "): ", err)
+		}
+	}()
+
+	s.handler.OnMessage(c, message)
+}
+
+func (s *tcpHandler) onDisconnected(c *tcpConn) {
+	defer func() {
+		if err := recover(); err != nil {
+			s.LogError("tcp connection OnDisconnected error(id=", c.id, ", rip=", c.remoteAddr, "): ", err)
+		}
+	}()
+
+	s.handler.OnDisconnected(c)
+}
+
+// 已关闭时返回false
+func (s *tcpHandler) add(c *tcpConn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+	s.wg.Add(1)
+	s.conns[c.id] = c
+
+	return true
+}
+
+func (s *tcpHandler) remove(c *tcpConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.conns, c.id)
+}
+
+func (s *tcpHandler) newConn(conn net.Conn, remoteAddr string) *tcpConn {
+	instance := &tcpConn{conn: conn}
+	instance.id = s.cid.New()
+	instance.remoteAddr = remoteAddr
+	if len(instance.remoteAddr) < 1 {
+		instance.remoteAddr = fmt.Sprint(conn.RemoteAddr())
+	}
+	instance.localAddr = fmt.Sprint(conn.LocalAddr())
+	instance.connectTime = time.Now()
+	instance.keys = make(map[string]interface{})
+
+	return instance
+}
+
+type tcpConn struct {
+	conn        net.Conn
+	id          uint64
+	remoteAddr  string
+	localAddr   string
+	connectTime time.Time
+	tls         bool
+	closed      bool
+
+	keys map[string]interface{}
+	kmu  sync.RWMutex
+	wmu  sync.Mutex
+	cmu  sync.Mutex
+}
+
+func (s *tcpConn) ID() uint64 {
+	return s.id
+}
+
+func (s *tcpConn) RemoteAddr() string {
+	return s.remoteAddr
+}
+
+func (s *tcpConn) LocalAddr() string {
+	return s.localAddr
+}
+
+func (s *tcpConn) ConnectTime() time.Time {
+	return s.connectTime
+}
+
+func (s *tcpConn) IsTls() bool {
+	return s.tls
+}
+
+func (s *tcpConn) Write(message []byte) error {
+	size := len(message)
+	buf := make([]byte, types.TcpMessageHeadSize+size)
+	binary.BigEndian.PutUint32(buf, uint32(size))
+	copy(buf[types.TcpMessageHeadSize:], message)
+
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+
+	_, err := s.conn.Write(buf)
+	return err
+}
+
+func (s *tcpConn) Close() error {
+	s.cmu.Lock()
+	s.closed = true
+	s.cmu.Unlock()
+
+	return s.conn.Close()
+}
+
+func (s *tcpConn) Set(key string, val interface{}) {
+	s.kmu.Lock()
+	defer s.kmu.Unlock()
+
+	s.keys[key] = val
+}
+
+func (s *tcpConn) Get(key string) (interface{}, bool) {
+	s.kmu.RLock()
+	defer s.kmu.RUnlock()
+
+	val, ok := s.keys[key]
+	return val, ok
+}
+
+func (s *tcpConn) Del(key string) bool {
+	s.kmu.Lock()
+	defer s.kmu.Unlock()
+
+	_, ok := s.keys[key]
+	if ok {
+		delete(s.keys, key)
+	}
+
+	return ok
+}
+
+func (s *tcpConn) isClosed() bool {
+	s.cmu.Lock()
+	defer s.cmu.Unlock()
+
+	return s.closed
+}
+
+func (s *tcpConn) read(maxSize int) ([]byte, error) {
+	head := make([]byte, types.TcpMessageHeadSize)
+	_, err := io.ReadFull(s.conn, head)
+	if err != nil {
+		return nil, err
+	}
+
+	size := binary.BigEndian.Uint32(head)
+	if uint64(size) > uint64(maxSize) {
+		return nil, fmt.Errorf("message size %d exceeds the limit %d", size, maxSize)
+	}
+
+	message := make([]byte, size)
+	_, err = io.ReadFull(s.conn, message)
+	if err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
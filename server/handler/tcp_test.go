@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"encoding/binary"
+	"github.com/csby/wsf/types"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestTcpHandler_ServeTCP(t *testing.T) {
+	echo := &tcpEchoHandler{disconnected: make(chan bool, 1)}
+	h, err := NewTcpHandler(nil, echo, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server, client := net.Pipe()
+	go h.ServeTCP(server, "192.168.1.8:12955")
+
+	err = writeTcpMessage(client, []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	message, err := readTcpMessage(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(message) != "hello" {
+		t.Fatal("expect 'hello'; actual ", string(message))
+	}
+	if echo.remoteAddr != "192.168.1.8:12955" {
+		t.Fatal("expect '192.168.1.8:12955'; actual ", echo.remoteAddr)
+	}
+
+	// exceeds max message size
+	go writeTcpMessage(client, make([]byte, 17))
+	select {
+	case <-echo.disconnected:
+	case <-time.After(time.Second):
+		t.Fatal("connection should be closed when message size exceeds the limit")
+	}
+}
+
+func TestTcpHandler_Close(t *testing.T) {
+	echo := &tcpEchoHandler{disconnected: make(chan bool, 1)}
+	h, err := NewTcpHandler(nil, echo, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server, client := net.Pipe()
+	defer client.Close()
+	go h.ServeTCP(server, "")
+	err = writeTcpMessage(client, []byte("hi"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = readTcpMessage(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.Close()
+	select {
+	case <-echo.disconnected:
+	default:
+		t.Fatal("Close should wait for OnDisconnected")
+	}
+
+	// connection accepted after Close should be closed immediately
+	server, client = net.Pipe()
+	h.ServeTCP(server, "")
+	_, err = client.Read(make([]byte, 1))
+	if err == nil {
+		t.Fatal("connection should be closed after Close")
+	}
+}
+
+type tcpEchoHandler struct {
+	remoteAddr   string
+	disconnected chan bool
+}
+
+func (s *tcpEchoHandler) OnConnected(conn types.TcpConn) bool {
+	s.remoteAddr = conn.RemoteAddr()
+	return true
+}
+
+func (s *tcpEchoHandler) OnMessage(conn types.TcpConn, message []byte) {
+	conn.Write(message)
+}
+
+func (s *tcpEchoHandler) OnDisconnected(conn types.TcpConn) {
+	s.disconnected <- true
+}
+
+func writeTcpMessage(conn net.Conn, message []byte) error {
+	head := make([]byte, types.TcpMessageHeadSize)
+	binary.BigEndian.PutUint32(head, uint32(len(message)))
+	_, err := conn.Write(append(head, message...))
+	return err
+}
+
+func readTcpMessage(conn net.Conn) ([]byte, error) {
+	head := make([]byte, types.TcpMessageHeadSize)
+	_, err := io.ReadFull(conn, head)
+	if err != nil {
+		return nil, err
+	}
+	message := make([]byte, binary.BigEndian.Uint32(head))
+	_, err = io.ReadFull(conn, message)
+	return message, err
+}
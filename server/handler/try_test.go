@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/csby/wsf/doc/model"
+	"github.com/csby/wsf/doc/web"
+	"github.com/csby/wsf/server/configure"
+	"github.com/csby/wsf/types"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHttpHandler_DocumentTry(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &configure.Configure{}
+	cfg.Http.Enabled = true
+	cfg.Http.Port = listener.Addr().(*net.TCPAddr).Port
+
+	h, err := NewHttpHandlerWithConfig(nil, &tryHandler{}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewUnstartedServer(h)
+	server.Listener.Close()
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	results, ok := h.(*httpHandler).router.Doc.Search("hello", 1).(model.SearchResultSlice)
+	if !ok || len(results) != 1 {
+		t.Fatal("function not found in document")
+	}
+	tryPath := types.Path{Prefix: web.ApiPath}
+	tryUrl := server.URL + strings.Replace(tryPath.New(web.ApiPathTryRun).Path(), ":id", results[0].ID, 1)
+	argument := &types.DocTryArgument{
+		Input: types.DocTryInput{
+			Params:  map[string]string{"name": "a b"},
+			Headers: map[string]string{"token": "t1"},
+		},
+	}
+
+	// 未通过凭证验证
+	result := postTry(t, tryUrl, "", argument)
+	if result.Code != types.ErrTokenInvalid.Code() {
+		t.Fatal("token check expected, but got:", result.Code)
+	}
+
+	result = postTry(t, tryUrl, "t1", argument)
+	if result.Code != 0 {
+		t.Fatal(result.Error.Summary, result.Error.Detail)
+	}
+	data, _ := json.Marshal(result.Data)
+	record := &types.DocTryRecord{}
+	err = json.Unmarshal(data, record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Output == nil || record.Output.Status != http.StatusOK || !strings.Contains(record.Output.Body, "hello a b") {
+		t.Fatal("invalid output:", string(data))
+	}
+	if record.Input.Headers["token"] == "t1" {
+		t.Fatal("token should not be saved")
+	}
+}
+
+func postTry(t *testing.T, url, token string, argument interface{}) *types.Result {
+	body, _ := json.Marshal(argument)
+	req, _ := http.NewRequest("POST", url, bytes.NewReader(body))
+	req.Header.Set("token", token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	buf := &bytes.Buffer{}
+	buf.ReadFrom(resp.Body)
+	result := &types.Result{}
+	err = result.Unmarshal(buf.Bytes())
+	if err != nil {
+		t.Fatal(err, buf.String())
+	}
+
+	return result
+}
+
+type tryHandler struct {
+	docHandler
+}
+
+func (s *tryHandler) Map(router types.Router) {
+	path := types.Path{Prefix: "/api"}
+	router.POST(path.New("/hello/:name"), s.checkToken, func(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) {
+		a.Success("hello " + p.ByName("name"))
+	}, func(doc types.Doc, method string, path types.HttpPath) {
+		catalog := doc.AddCatalog("接口")
+		catalog.AddFunction(method, path, "hello")
+	})
+}
+
+func (s *tryHandler) Extend() types.HttpHandlerExtend {
+	return &tryHandlerExtend{check: s.checkToken}
+}
+
+func (s *tryHandler) checkToken(w http.ResponseWriter, r *http.Request, p types.Params, a types.Assistant) bool {
+	if a.Token() != "t1" {
+		a.Error(types.ErrTokenInvalid)
+		return true
+	}
+	a.Set(types.RouterKeyAccount, "admin")
+
+	return false
+}
+
+type tryHandlerExtend struct {
+	check types.RouterPreHandle
+}
+
+func (s *tryHandlerExtend) Restart() func() error                   { return nil }
+func (s *tryHandlerExtend) RedirectToHttps() bool                   { return false }
+func (s *tryHandlerExtend) DocumentEnabled() bool                   { return true }
+func (s *tryHandlerExtend) DocumentRoot() string                    { return "" }
+func (s *tryHandlerExtend) ServerInfo() *types.ServerInformation    { return nil }
+func (s *tryHandlerExtend) DocumentTryCheck() types.RouterPreHandle { return s.check }
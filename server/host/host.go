@@ -1,8 +1,10 @@
 package host
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
+	"io"
 	"github.com/csby/security/certificate"
 	"github.com/csby/wsf/server/configure"
 	"github.com/csby/wsf/server/handler"
@@ -11,6 +13,13 @@ import (
 	"net/http"
 	"strings"
 	"sync"
+	"time"
+)
+
+const (
+	// PROXY协议v1头部最大长度(包括\r\n)
+	proxyHeaderMaxLength = 107
+	proxyHeaderTimeout   = 5 * time.Second
 )
 
 func NewHost(log types.Log, cfg *configure.Configure, httpHandler types.HttpHandler, tcpHandler types.TcpHandler) types.Host {
@@ -29,6 +38,14 @@ type host struct {
 
 	httpServer  *http.Server
 	httpsServer *http.Server
+	httpRouter  handler.HttpHandler
+	tcpListener net.Listener
+	tcpServer   handler.TcpHandler
+	tcpMutex    sync.Mutex
+	tcpClosed   bool
+
+	closeMutex sync.Mutex
+	closed     bool
 }
 
 func (s *host) Run() error {
@@ -36,14 +53,23 @@ func (s *host) Run() error {
 		return fmt.Errorf(s.LogError("invalid configure: nil"))
 	}
 
+	s.closeMutex.Lock()
+	s.closed = false
+	s.closeMutex.Unlock()
+
+	s.tcpMutex.Lock()
+	s.tcpClosed = false
+	s.tcpMutex.Unlock()
+
 	wg := &sync.WaitGroup{}
 
 	if s.httpHandler != nil {
-		router, err := handler.NewHttpHandler(s.GetLog(), s.httpHandler)
+		router, err := handler.NewHttpHandlerWithConfig(s.GetLog(), s.httpHandler, s.cfg)
 		if err != nil {
 			s.LogError("NewHttpHandler error: ", err)
 			return err
 		}
+		s.httpRouter = router
 
 		// http
 		if s.cfg.Http.Enabled {
@@ -53,7 +79,7 @@ func (s *host) Run() error {
 				defer s.LogInfo("http server stopped")
 
 				err := s.runHttp(router)
-				if err != nil {
+				if err != nil && err != http.ErrServerClosed {
 					s.LogError("http server error: ", err)
 				}
 
@@ -68,7 +94,7 @@ func (s *host) Run() error {
 				defer s.LogInfo("https server stopped")
 
 				err := s.runHttps(router)
-				if err != nil {
+				if err != nil && err != http.ErrServerClosed {
 					s.LogError("https server error: ", err)
 				}
 			}()
@@ -95,22 +121,85 @@ func (s *host) Run() error {
 	return nil
 }
 
-func (s *host) Close() (err error) {
-	if s.httpServer != nil {
-		e := s.httpServer.Close()
+// 可重复调用, 仅第一次调用生效
+func (s *host) Close() error {
+	s.closeMutex.Lock()
+	if s.closed {
+		s.closeMutex.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.closeMutex.Unlock()
+
+	timeout := s.shutdownTimeout()
+	s.LogInfo("host closing, timeout: ", timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	errs := make(chan error, 4)
+	wg := &sync.WaitGroup{}
+
+	servers := []*http.Server{s.httpServer, s.httpsServer}
+	for _, server := range servers {
+		if server == nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(server *http.Server) {
+			defer wg.Done()
+
+			// stop listening and wait for active requests
+			e := server.Shutdown(ctx)
+			if e != nil {
+				errs <- e
+				server.Close()
+			}
+		}(server)
+	}
+
+	s.tcpMutex.Lock()
+	s.tcpClosed = true
+	listener := s.tcpListener
+	s.tcpListener = nil
+	tcpServer := s.tcpServer
+	s.tcpMutex.Unlock()
+	if listener != nil {
+		e := listener.Close()
 		if e != nil {
-			err = e
+			errs <- e
 		}
 	}
+	if tcpServer != nil {
+		tcpServer.Close()
+	}
 
-	if s.httpsServer != nil {
-		e := s.httpsServer.Close()
+	if s.httpRouter != nil {
+		// close websocket channels and wait for hijacked connections and post routing
+		e := s.httpRouter.Shutdown(ctx)
 		if e != nil {
-			err = e
+			s.LogWarning("wait for http requests finish error: ", e)
+			errs <- e
 		}
 	}
 
-	return
+	wg.Wait()
+	close(errs)
+
+	var err error = nil
+	for e := range errs {
+		err = e
+	}
+
+	return err
+}
+
+func (s *host) shutdownTimeout() time.Duration {
+	if s.cfg == nil || s.cfg.Service.ShutdownTimeout <= 0 {
+		return 10 * time.Second
+	}
+
+	return time.Duration(s.cfg.Service.ShutdownTimeout) * time.Second
 }
 
 func (s *host) runHttp(handler http.Handler) error {
@@ -143,39 +232,20 @@ func (s *host) runHttps(handler http.Handler) error {
 		}
 	}()
 
-	caFilePath := s.cfg.Https.Cert.Ca.File
-	s.LogInfo("https server ca file: ", caFilePath)
-	pfxFilePath := s.cfg.Https.Cert.Server.File
-	s.LogInfo("https server pfx file: ", pfxFilePath)
-	pfx := &certificate.CrtPfx{}
-	err := pfx.FromFile(pfxFilePath, s.cfg.Https.Cert.Server.Password)
+	tlsConfig, err := s.newTlsConfig("https", &s.cfg.Https.Cert, s.cfg.Https.RequestClientCert)
 	if err != nil {
-		return fmt.Errorf("load pfx file fail: %v", err)
+		return err
 	}
 
 	addr := fmt.Sprintf("%s:%d", s.cfg.Https.Address, s.cfg.Https.Port)
 	s.httpsServer = &http.Server{
-		Addr:    addr,
-		Handler: handler,
-		TLSConfig: &tls.Config{
-			Certificates: pfx.TlsCertificates(),
-			ClientAuth:   tls.NoClientCert,
-		},
+		Addr:      addr,
+		Handler:   handler,
+		TLSConfig: tlsConfig,
 	}
 	if s.cfg.Https.BehindProxy {
 		s.httpsServer.ProxyRemoteAddr = s.getRemoteAddr
 	}
-	if s.cfg.Https.RequestClientCert {
-		s.httpsServer.TLSConfig.ClientAuth = tls.RequireAndVerifyClientCert
-	}
-	if len(caFilePath) > 0 {
-		crt := &certificate.Crt{}
-		err = crt.FromFile(caFilePath)
-		if err != nil {
-			return fmt.Errorf("load ca file fail: %v", err)
-		}
-		s.httpsServer.TLSConfig.ClientCAs = crt.Pool()
-	}
 
 	s.LogInfo("https server running on \"", addr, "\"")
 	err = s.httpsServer.ListenAndServeTLS("", "")
@@ -191,10 +261,117 @@ func (s *host) runTcp() error {
 		}
 	}()
 
+	tcpServer, err := handler.NewTcpHandler(s.GetLog(), s.tcpHandler, s.cfg.Tcp.MaxMessageSize)
+	if err != nil {
+		return err
+	}
+
+	var tlsConfig *tls.Config = nil
+	if len(s.cfg.Tcp.Cert.Server.File) > 0 {
+		tlsConfig, err = s.newTlsConfig("tcp", &s.cfg.Tcp.Cert, s.cfg.Tcp.RequestClientCert)
+		if err != nil {
+			return err
+		}
+	}
+
 	addr := fmt.Sprintf("%s:%d", s.cfg.Tcp.Address, s.cfg.Tcp.Port)
-	s.LogInfo("tcp server running on \"", addr, "\"")
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	s.tcpMutex.Lock()
+	if s.tcpClosed {
+		// Close已执行
+		s.tcpMutex.Unlock()
+		listener.Close()
+		return nil
+	}
+	s.tcpListener = listener
+	s.tcpServer = tcpServer
+	s.tcpMutex.Unlock()
+	s.LogInfo("tcp server running on \"", addr, "\", tls=", tlsConfig != nil)
 
-	return nil
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				time.Sleep(5 * time.Millisecond)
+				continue
+			}
+			if s.takeTcpListener() == nil {
+				// closed by Close
+				return nil
+			}
+			listener.Close()
+			return err
+		}
+
+		go s.serveTcp(tcpServer, conn, tlsConfig)
+	}
+}
+
+func (s *host) takeTcpListener() net.Listener {
+	s.tcpMutex.Lock()
+	defer s.tcpMutex.Unlock()
+
+	listener := s.tcpListener
+	s.tcpListener = nil
+
+	return listener
+}
+
+func (s *host) serveTcp(tcpServer handler.TcpHandler, conn net.Conn, tlsConfig *tls.Config) {
+	defer func() {
+		if err := recover(); err != nil {
+			s.LogError("tcp connection exception: ", err)
+		}
+	}()
+
+	remoteAddr := fmt.Sprint(conn.RemoteAddr())
+	if s.cfg.Tcp.BehindProxy {
+		remoteAddr = s.getRemoteAddr(conn)
+		if len(remoteAddr) < 1 {
+			s.LogWarning("tcp connection from '", conn.RemoteAddr(), "' closed: invalid proxy protocol header")
+			conn.Close()
+			return
+		}
+	}
+
+	if tlsConfig != nil {
+		conn = tls.Server(conn, tlsConfig)
+	}
+
+	tcpServer.ServeTCP(conn, remoteAddr)
+}
+
+func (s *host) newTlsConfig(name string, cert *configure.Certificate, requestClientCert bool) (*tls.Config, error) {
+	caFilePath := cert.Ca.File
+	s.LogInfo(name, " server ca file: ", caFilePath)
+	pfxFilePath := cert.Server.File
+	s.LogInfo(name, " server pfx file: ", pfxFilePath)
+	pfx := &certificate.CrtPfx{}
+	err := pfx.FromFile(pfxFilePath, cert.Server.Password)
+	if err != nil {
+		return nil, fmt.Errorf("load pfx file fail: %v", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: pfx.TlsCertificates(),
+		ClientAuth:   tls.NoClientCert,
+	}
+	if requestClientCert {
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	if len(caFilePath) > 0 {
+		crt := &certificate.Crt{}
+		err = crt.FromFile(caFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("load ca file fail: %v", err)
+		}
+		tlsConfig.ClientCAs = crt.Pool()
+	}
+
+	return tlsConfig, nil
 }
 
 func (s *host) getRemoteAddr(conn net.Conn) string {
@@ -213,9 +390,16 @@ func (s *host) getRemoteAddr(conn net.Conn) string {
 		rawConn = tlsConn.RawConn()
 	}
 
+	// 限制读取时间及长度, 避免客户端不发送换行符时占用连接
+	rawConn.SetReadDeadline(time.Now().Add(proxyHeaderTimeout))
+	defer rawConn.SetReadDeadline(time.Time{})
+
 	buf := make([]byte, 1)
 	sb := &strings.Builder{}
-	for {
+	for length := 0; ; length++ {
+		if length >= proxyHeaderMaxLength {
+			return ""
+		}
 		_, e := rawConn.Read(buf)
 		if e != nil {
 			return ""
@@ -241,3 +425,7 @@ func (s *host) getRemoteAddr(conn net.Conn) string {
 		return ""
 	}
 }
+
+func (s *host) ExportDoc(w io.Writer, format, title string) error {
+	return handler.ExportDoc(s.httpHandler, w, format, title)
+}
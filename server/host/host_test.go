@@ -0,0 +1,70 @@
+package host
+
+import (
+	"github.com/csby/wsf/server/configure"
+	"github.com/csby/wsf/types"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHost_GetRemoteAddr(t *testing.T) {
+	s := &host{cfg: &configure.Configure{}}
+
+	server, client := net.Pipe()
+	go func() {
+		client.Write([]byte("PROXY TCP4 192.168.123.254 12955 192.168.123.81 8088\r\n"))
+	}()
+	addr := s.getRemoteAddr(server)
+	if addr != "192.168.123.254:12955" {
+		t.Fatal("invalid addr:", addr)
+	}
+	server.Close()
+	client.Close()
+
+	// 超过最大长度且无换行符
+	server, client = net.Pipe()
+	go func() {
+		client.Write([]byte("PROXY TCP4 " + strings.Repeat("1", 200)))
+	}()
+	addr = s.getRemoteAddr(server)
+	if addr != "" {
+		t.Fatal("header too long should be rejected, but got:", addr)
+	}
+	server.Close()
+	client.Close()
+}
+
+func TestHost_CloseBeforeTcpListen(t *testing.T) {
+	cfg := &configure.Configure{}
+	cfg.Tcp.Address = "127.0.0.1"
+	s := &host{cfg: cfg, tcpHandler: &tcpHandler{}}
+	s.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.runTcp()
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("tcp listener should be closed when host has been closed")
+	}
+}
+
+type tcpHandler struct {
+}
+
+func (s *tcpHandler) OnConnected(conn types.TcpConn) bool {
+	return true
+}
+
+func (s *tcpHandler) OnMessage(conn types.TcpConn, message []byte) {
+}
+
+func (s *tcpHandler) OnDisconnected(conn types.TcpConn) {
+}
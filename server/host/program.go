@@ -20,6 +20,12 @@ func (s *program) Start(svc service.Service) error {
 }
 
 func (s *program) Stop(svc service.Service) error {
+	if s.server != nil {
+		err := s.server.Close()
+		if err != nil {
+			s.LogError("close server error: ", err)
+		}
+	}
 	s.LogInfo("service '", svc.String(), "' stopped")
 
 	return nil
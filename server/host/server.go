@@ -2,6 +2,7 @@ package host
 
 import (
 	"fmt"
+	"io"
 	"github.com/csby/wsf/types"
 	"github.com/kardianos/service"
 )
@@ -96,3 +97,12 @@ func (s *server) Status() (types.ServerStatus, error) {
 
 	return types.ServerStatus(status), nil
 }
+
+func (s *server) ExportDoc(w io.Writer, format, title string) error {
+	exporter, ok := s.program.server.(types.ServerDocExporter)
+	if !ok {
+		return fmt.Errorf("host does not support exporting document")
+	}
+
+	return exporter.ExportDoc(w, format, title)
+}
@@ -0,0 +1,32 @@
+package types
+
+import "time"
+
+const (
+	AccessFormatCommon   = "common"
+	AccessFormatCombined = "combined"
+	AccessFormatJson     = "json"
+)
+
+// 访问记录, 每个HTTP请求完成后生成
+type AccessEntry struct {
+	RID       uint64        // 请求标识
+	RIP       string        // 客户端IP地址
+	Account   string        // 凭证对应的账号, 未验证时为空
+	Time      time.Time     // 请求开始时间
+	Elapsed   time.Duration // 处理耗时
+	Method    string        // 请求方法
+	Schema    string        // http或https
+	Host      string        // 请求主机
+	Uri       string        // 请求地址, 包括查询参数
+	Proto     string        // 协议版本, 如: HTTP/1.1
+	Status    int           // 响应状态码
+	Size      int64         // 响应内容大小(字节)
+	Referer   string        // 来源页面
+	UserAgent string        // 客户端标识
+}
+
+// 访问日志
+type AccessLog interface {
+	Write(entry *AccessEntry)
+}
@@ -1,8 +1,12 @@
 package types
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
+	"golang.org/x/crypto/ssh/terminal"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
@@ -18,6 +22,13 @@ type SvcArgs struct {
 	Start     bool
 	Stop      bool
 	Restart   bool
+
+	HashPassword  bool
+	HashAlgorithm string
+
+	ExportDoc    string
+	ExportFormat string
+	ExportTitle  string
 }
 
 func (s *SvcArgs) Parse(key, value string) {
@@ -39,6 +50,16 @@ func (s *SvcArgs) Parse(key, value string) {
 		s.Stop = true
 	} else if key == strings.ToLower("-restart") {
 		s.Restart = true
+	} else if key == strings.ToLower("-hash-password") {
+		s.HashPassword = true
+	} else if key == strings.ToLower("-hash-algorithm") {
+		s.HashAlgorithm = value
+	} else if key == strings.ToLower("-export-doc") {
+		s.ExportDoc = value
+	} else if key == strings.ToLower("-export-format") {
+		s.ExportFormat = value
+	} else if key == strings.ToLower("-export-title") {
+		s.ExportTitle = value
 	}
 }
 
@@ -52,6 +73,14 @@ func (s *SvcArgs) ShowHelp(cfgFolder, cfgName string) {
 	s.ShowLine("  -start:", "[可选]启动服务")
 	s.ShowLine("  -stop:", "[可选]停止服务")
 	s.ShowLine("  -restart:", "[可选]重启服务")
+
+	s.ShowLine("  -hash-password:", "[可选]生成密码的哈希值(用于配置文件中的用户密码), 密码从标准输入读取(不回显), 如: -hash-password")
+	s.ShowLine("  -hash-algorithm:", fmt.Sprintf("[可选]哈希算法: %s(默认), %s, %s, %s",
+		PasswordHashBcrypt, PasswordHashArgon2id, PasswordHashPbkdf2Sha256, PasswordHashPbkdf2Sha512))
+
+	s.ShowLine("  -export-doc:", "[可选]导出接口文档到指定文件(不启动服务), 如: -export-doc=/home/api.md")
+	s.ShowLine("  -export-format:", "[可选]接口文档格式: markdown(默认), html")
+	s.ShowLine("  -export-title:", "[可选]接口文档标题, 默认: 接口文档")
 }
 
 func (s *SvcArgs) ShowLine(label, value string) {
@@ -65,6 +94,26 @@ func (s *SvcArgs) ShowLine(label, value string) {
 }
 
 func (s *SvcArgs) Execute(server Server) {
+	if s.HashPassword {
+		hashed, err := s.hashPassword(os.Stdin)
+		if err != nil {
+			fmt.Println("hash password fail: ", err)
+		} else {
+			fmt.Println(hashed)
+		}
+		os.Exit(27)
+	}
+
+	if len(s.ExportDoc) > 0 {
+		err := s.exportDoc(server)
+		if err != nil {
+			fmt.Println("export document fail: ", err)
+		} else {
+			fmt.Println("export document to ", s.ExportDoc, " success")
+		}
+		os.Exit(28)
+	}
+
 	if server == nil {
 		return
 	}
@@ -130,6 +179,51 @@ func (s *SvcArgs) Execute(server Server) {
 	}
 }
 
+// 从标准输入读取密码, 终端输入时不回显
+func (s *SvcArgs) hashPassword(in *os.File) (string, error) {
+	var password []byte
+	fd := int(in.Fd())
+	if terminal.IsTerminal(fd) {
+		fmt.Print("password: ")
+		value, err := terminal.ReadPassword(fd)
+		fmt.Println("")
+		if err != nil {
+			return "", err
+		}
+		password = value
+	} else {
+		line, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		password = []byte(strings.TrimRight(line, "\r\n"))
+	}
+	if len(password) < 1 {
+		return "", fmt.Errorf("password is empty")
+	}
+
+	return HashPassword(string(password), s.HashAlgorithm)
+}
+
+func (s *SvcArgs) exportDoc(server Server) error {
+	exporter, ok := server.(ServerDocExporter)
+	if !ok {
+		return fmt.Errorf("server does not support exporting document")
+	}
+
+	title := s.ExportTitle
+	if len(title) < 1 {
+		title = "接口文档"
+	}
+	buf := &bytes.Buffer{}
+	err := exporter.ExportDoc(buf, s.ExportFormat, title)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(s.ExportDoc, buf.Bytes(), 0644)
+}
+
 type SvcInfo struct {
 	Name     string   `json:"name" note:"服务名称"`
 	Version  string   `json:"version" note:"版本号"`
@@ -0,0 +1,30 @@
+package types
+
+import (
+	"os"
+	"testing"
+)
+
+func TestSvcArgs_HashPassword(t *testing.T) {
+	args := &SvcArgs{}
+	args.Parse("-hash-password", "ignored")
+	if !args.HashPassword {
+		t.Fatal("hash password should be enabled")
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	w.WriteString("P@ssw0rd\n")
+	w.Close()
+
+	hashed, err := args.hashPassword(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyPassword(hashed, "P@ssw0rd") {
+		t.Fatal("password should be read from stdin")
+	}
+}
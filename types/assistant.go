@@ -39,4 +39,6 @@ type Assistant interface {
 	NewGuid() string
 	RSAPublicKey() string
 	RSADecrypt(data string) (string, error)
+	RSASign(data []byte) ([]byte, error)
+	RSAVerify(data, signature []byte) error
 }
@@ -0,0 +1,29 @@
+package types
+
+const (
+	// 审计记录的操作对象, 由接口处理函数设置, 未设置时使用上传的文件名称或输入内容
+	AuditKeyTarget = "audit.target"
+)
+
+type AuditRecord struct {
+	Time    DateTime `json:"time" note:"操作时间"`
+	RID     uint64   `json:"rid" note:"请求标识"`
+	Account string   `json:"account" note:"操作账号"`
+	IP      string   `json:"ip" note:"来源IP地址"`
+	Action  string   `json:"action" note:"操作, 如: site.root.upload"`
+	Target  string   `json:"target" note:"操作对象, 如: 文件名称"`
+	Method  string   `json:"method" note:"请求方法"`
+	Path    string   `json:"path" note:"请求路径"`
+	Success bool     `json:"success" note:"是否成功"`
+	Error   string   `json:"error,omitempty" note:"失败原因"`
+}
+
+type AuditFilter struct {
+	Account string    `json:"account" note:"操作账号, 为空时不限制"`
+	Action  string    `json:"action" note:"操作, 为空时不限制, 以.结尾时按前缀匹配, 如: site."`
+	IP      string    `json:"ip" note:"来源IP地址, 为空时不限制"`
+	Keyword string    `json:"keyword" note:"关键字, 在操作对象及请求路径中匹配, 为空时不限制"`
+	Start   *DateTime `json:"start" note:"开始时间, 为空时不限制"`
+	End     *DateTime `json:"end" note:"结束时间, 为空时不限制"`
+	Limit   int       `json:"limit" note:"最大返回数量, 默认100, 最大1000"`
+}
@@ -51,3 +51,58 @@ func (s *Base) LogDebug(v ...interface{}) string {
 
 	return s.log.Debug(v...)
 }
+
+func (s *Base) LogErrorKV(msg string, kv ...interface{}) string {
+	if s.log == nil {
+		return ""
+	}
+	if log, ok := s.log.(LogKV); ok {
+		return log.ErrorKV(msg, kv...)
+	}
+
+	return s.log.Error(LogKVText(msg, kv...))
+}
+
+func (s *Base) LogWarningKV(msg string, kv ...interface{}) string {
+	if s.log == nil {
+		return ""
+	}
+	if log, ok := s.log.(LogKV); ok {
+		return log.WarningKV(msg, kv...)
+	}
+
+	return s.log.Warning(LogKVText(msg, kv...))
+}
+
+func (s *Base) LogInfoKV(msg string, kv ...interface{}) string {
+	if s.log == nil {
+		return ""
+	}
+	if log, ok := s.log.(LogKV); ok {
+		return log.InfoKV(msg, kv...)
+	}
+
+	return s.log.Info(LogKVText(msg, kv...))
+}
+
+func (s *Base) LogTraceKV(msg string, kv ...interface{}) string {
+	if s.log == nil {
+		return ""
+	}
+	if log, ok := s.log.(LogKV); ok {
+		return log.TraceKV(msg, kv...)
+	}
+
+	return s.log.Trace(LogKVText(msg, kv...))
+}
+
+func (s *Base) LogDebugKV(msg string, kv ...interface{}) string {
+	if s.log == nil {
+		return ""
+	}
+	if log, ok := s.log.(LogKV); ok {
+		return log.DebugKV(msg, kv...)
+	}
+
+	return s.log.Debug(LogKVText(msg, kv...))
+}
@@ -1,11 +1,43 @@
 package types
 
+import "time"
+
 const (
 	ContentTypeJson = "application/json"
+	ContentTypeYaml = "application/x-yaml"
+)
+
+const (
+	OpenApiFormatJson = "json"
+	OpenApiFormatYaml = "yaml"
 )
 
+type OpenApiInfo struct {
+	Title       string   `json:"title" note:"标题"`
+	Description string   `json:"description" note:"说明"`
+	Version     string   `json:"version" note:"版本号"`
+	Servers     []string `json:"servers" note:"服务地址, 如: https://127.0.0.1:8443"`
+}
+
+type DocSearchFilter struct {
+	Keyword string `json:"keyword" required:"true" note:"关键字, 多个关键字以空格分隔, 须全部匹配"`
+	Limit   int    `json:"limit" note:"最大返回数量, 默认50"`
+}
+
+type Deprecation struct {
+	Reason      string    `json:"reason" note:"弃用原因"`
+	Replacement string    `json:"replacement" note:"替代接口, 如: /opt.api/v2/login"`
+	Sunset      *DateTime `json:"sunset" note:"停用时间, 为空表示未定"`
+}
+
 type Function interface {
 	SetNote(v string)
+	// 标记为已弃用, replacement为替代接口, 调用时响应头部包含Deprecation并记录警告日志
+	SetDeprecated(reason, replacement string)
+	// 设置停用时间, 调用时响应头部包含Sunset, 未标记弃用时同时标记为已弃用
+	SetSunset(t time.Time)
+	// 设置接口起始版本, 如: 1.2.0
+	SetSince(version string)
 	SetTokenType(v int)
 	SetInputContentType(v string)
 	AddInputHeader(required bool, name, note, defaultValue string, optionValues ...string)
@@ -21,6 +53,16 @@ type Function interface {
 	SetOutputDataExample(v interface{})
 	AddOutputError(err ErrorCode)
 	AddOutputErrorCustom(code int, summary string)
+	// 添加输出错误及其示例, detail为示例中的错误详细信息
+	AddOutputErrorExample(err ErrorCode, detail string)
+
+	// 添加命名的输入/输出示例, 名称相同时替换
+	AddInputExample(name, note string, v interface{})
+	AddOutputExample(name, note string, v interface{})
+	// 添加输入/输出变体(oneOf): 数据结构由区分字段discriminator的值value确定, v为该变体的完整示例
+	// 如: AddOutputVariant("id", WSOptUserLogin, "用户登陆", &SocketMessage{ID: WSOptUserLogin, Data: &OnlineUser{}})
+	AddInputVariant(discriminator string, value interface{}, name string, v interface{})
+	AddOutputVariant(discriminator string, value interface{}, name string, v interface{})
 }
 
 type Catalog interface {
@@ -32,8 +74,18 @@ type Doc interface {
 	Enable() bool
 	AddCatalog(name string) Catalog
 	Catalogs() interface{}
+	// 在接口名称、说明、地址及参数中搜索, 按相关度从高到低返回, limit小于1时默认返回50条
+	Search(keyword string, limit int) interface{}
 	Function(id, schema, host string) (interface{}, error)
 	OnFunctionReady(f func(index int, method, path, name string))
 	TokenUI(id string) (interface{}, error)
 	TokenCreate(id string, items []TokenAuth, a Assistant) (string, ErrorCode, error)
+	// 导出OpenAPI 3文档, format: json(默认)或yaml
+	OpenApi(info *OpenApiInfo, format string) ([]byte, error)
+	// 导出websocket接口的AsyncAPI 2文档, format: json(默认)或yaml
+	AsyncApi(info *OpenApiInfo, format string) ([]byte, error)
+	// 获取接口的输入验证函数, 接口未定义时返回nil, body表示是否需要验证请求内容(如上传文件的接口不需要)
+	InputValidator(method, path string) (validator InputValidator, body bool)
+	// 获取接口的弃用信息, 接口未定义或未弃用时返回nil
+	Deprecation(method, path string) *Deprecation
 }
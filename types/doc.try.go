@@ -0,0 +1,46 @@
+package types
+
+type DocTryInput struct {
+	Params  map[string]string `json:"params" note:"路径参数, 如: {\"id\": \"1\"}"`
+	Headers map[string]string `json:"headers" note:"请求头部, 如: {\"token\": \"...\"}, 凭证等敏感信息不会保存"`
+	Queries map[string]string `json:"queries" note:"请求参数"`
+	Body    string            `json:"body" note:"请求内容"`
+}
+
+type DocTryOutput struct {
+	Status  int               `json:"status" note:"HTTP状态码"`
+	Headers map[string]string `json:"headers" note:"响应头部"`
+	Body    string            `json:"body" note:"响应内容, 超过1MB时截断"`
+	Elapsed int64             `json:"elapsed" note:"耗时, 单位毫秒"`
+	Error   string            `json:"error,omitempty" note:"请求失败时的错误信息"`
+}
+
+type DocTryRecord struct {
+	ID         string        `json:"id" note:"记录标识"`
+	Name       string        `json:"name" note:"名称, 仅保存的请求有效"`
+	FunctionID string        `json:"functionId" note:"接口标识"`
+	Method     string        `json:"method" note:"接口方法"`
+	Path       string        `json:"path" note:"接口地址"`
+	Url        string        `json:"url" note:"请求地址"`
+	Time       DateTime      `json:"time" note:"时间"`
+	Input      DocTryInput   `json:"input" note:"输入"`
+	Output     *DocTryOutput `json:"output,omitempty" note:"输出, 仅历史记录有效"`
+}
+
+type DocTryArgument struct {
+	Input DocTryInput `json:"input" note:"输入"`
+}
+
+type DocTrySave struct {
+	Name       string      `json:"name" required:"true" note:"名称, 同一接口下名称相同时替换"`
+	FunctionID string      `json:"functionId" required:"true" note:"接口标识"`
+	Input      DocTryInput `json:"input" note:"输入"`
+}
+
+type DocTryFilter struct {
+	FunctionID string `json:"functionId" note:"接口标识, 为空时不限制"`
+}
+
+type DocTryDelete struct {
+	ID string `json:"id" required:"true" note:"记录标识"`
+}
@@ -17,11 +17,13 @@ var (
 	ErrTokenEmpty   = NewErrorCode(101, "缺少凭证")
 	ErrTokenInvalid = NewErrorCode(101, "凭证无效")
 	ErrTokenIllegal = NewErrorCode(101, "凭证非法")
+	ErrForbidden    = NewErrorCode(102, "没有权限")
 
 	ErrLoginCaptchaInvalid           = NewErrorCode(201, "验证码无效")
 	ErrLoginAccountNotExit           = NewErrorCode(202, "账号不存在")
 	ErrLoginPasswordInvalid          = NewErrorCode(203, "密码不正确")
 	ErrLoginAccountOrPasswordInvalid = NewErrorCode(204, "账号或密码不正确")
+	ErrLoginLocked                   = NewErrorCode(205, "登陆已锁定")
 )
 
 type Error interface {
@@ -19,5 +19,29 @@ type HttpHandlerExtend interface {
 	ServerInfo() *ServerInformation
 }
 
+// 以下为HttpHandlerExtend的可选扩展, 按需实现
+
+type HttpHandlerDocumentStore interface {
+	DocumentStore() string // 接口文档调试记录文件夹, 每个账号一个文件, 为空时仅保存在内存中
+}
+
+type HttpHandlerDocumentTry interface {
+	DocumentTryCheck() RouterPreHandle // 接口文档调试的凭证验证, 验证通过后须设置RouterKeyAccount, 为nil时不提供调试接口
+}
+
+type HttpHandlerSocketChannels interface {
+	SocketChannels() SocketChannelCollection // 停止服务时关闭所有通道(须实现SocketChannelShutdown), 可为nil
+}
+
+type HttpHandlerAccessLog interface {
+	AccessLog() AccessLog // 访问日志, 未实现或为nil时根据配置(log.access)创建
+}
+
 type TcpHandler interface {
+	// 新连接建立(TLS握手已完成), 返回false时关闭该连接
+	OnConnected(conn TcpConn) bool
+	// 收到一条完整消息(不包括长度头部)
+	OnMessage(conn TcpConn, message []byte)
+	// 连接已断开
+	OnDisconnected(conn TcpConn)
 }
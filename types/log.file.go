@@ -0,0 +1,30 @@
+package types
+
+type LogFile struct {
+	Name       string   `json:"name" note:"文件名称"`
+	Size       int64    `json:"size" note:"文件大小(字节)"`
+	ModTime    DateTime `json:"modTime" note:"修改时间"`
+	Compressed bool     `json:"compressed" note:"是否已压缩(gzip)"`
+}
+
+type LogFileFilter struct {
+	Name string `json:"name" required:"true" note:"文件名称"`
+}
+
+type LogSearchFilter struct {
+	Name    string    `json:"name" required:"true" note:"文件名称"`
+	Level   string    `json:"level" note:"级别, 多个以|分隔, 如: error|warning, 为空时不限制"`
+	Start   *DateTime `json:"start" note:"开始时间, 为空时不限制"`
+	End     *DateTime `json:"end" note:"结束时间, 为空时不限制"`
+	Keyword string    `json:"keyword" note:"关键字, 不区分大小写, 为空时不限制"`
+	Limit   int       `json:"limit" note:"最大返回数量, 默认200, 最大2000"`
+}
+
+type LogSearchResult struct {
+	Total   int         `json:"total" note:"匹配的总数"`
+	Entries []*LogEntry `json:"entries" note:"匹配的日志(按文件中的顺序), 最多返回limit条"`
+}
+
+type LogTailFilter struct {
+	Level string `json:"level" note:"订阅的级别, 多个以|分隔, 如: error|warning, 为空时取消订阅"`
+}
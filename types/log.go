@@ -1,5 +1,12 @@
 package types
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
 type Log interface {
 	Error(v ...interface{}) string
 	Warning(v ...interface{}) string
@@ -7,3 +14,76 @@ type Log interface {
 	Trace(v ...interface{}) string
 	Debug(v ...interface{}) string
 }
+
+// 结构化日志, kv为键值对, 如: InfoKV("http request", "rid", rid, "path", path)
+type LogKV interface {
+	ErrorKV(msg string, kv ...interface{}) string
+	WarningKV(msg string, kv ...interface{}) string
+	InfoKV(msg string, kv ...interface{}) string
+	TraceKV(msg string, kv ...interface{}) string
+	DebugKV(msg string, kv ...interface{}) string
+}
+
+type LogEntry struct {
+	Time    DateTime `json:"time" note:"时间"`
+	Level   string   `json:"level" note:"级别: error, warning, info, trace, debug"`
+	Caller  string   `json:"caller,omitempty" note:"调用位置"`
+	Message string   `json:"message" note:"内容"`
+	Line    int      `json:"line,omitempty" note:"在文件中的行号(从1开始), 仅搜索结果有效"`
+}
+
+// 支持实时查看的日志, 每条输出的日志均通知监听函数
+// 监听函数中不可输出日志
+type LogTail interface {
+	AddTailListener(listener func(entry *LogEntry))
+}
+
+type LogLevel struct {
+	Level   string    `json:"level" note:"当前生效的级别, 如: error|warning|info"`
+	Default string    `json:"default" note:"配置的级别"`
+	Expires *DateTime `json:"expires" note:"自动恢复为配置级别的时间, 为空表示不自动恢复"`
+}
+
+type LogLevelArgument struct {
+	Level    string `json:"level" note:"级别, 多个以|分隔, 如: error|warning|info|debug, 为空时恢复为配置的级别"`
+	Duration int64  `json:"duration" note:"有效时长, 单位分钟, 到期后自动恢复为配置的级别, 0表示不自动恢复"`
+}
+
+// 支持运行时修改级别的日志, 每次修改(包括自动恢复)均输出日志并通知监听函数
+type LogLevelControl interface {
+	GetLevel() *LogLevel
+	// 修改级别, duration小于等于0时不自动恢复
+	SetLevel(level string, duration time.Duration) (*LogLevel, error)
+	// 恢复为配置的级别
+	ResetLevel() *LogLevel
+	AddLevelListener(listener func(level *LogLevel))
+}
+
+// 将键值对格式化为文本, 如: "http request rid=1 path=/api/login", 用于不支持结构化的日志
+func LogKVText(msg string, kv ...interface{}) string {
+	sb := &strings.Builder{}
+	sb.WriteString(msg)
+
+	count := len(kv)
+	for index := 0; index < count; index += 2 {
+		sb.WriteString(" ")
+		if index+1 < count {
+			sb.WriteString(fmt.Sprint(kv[index]))
+			sb.WriteString("=")
+			sb.WriteString(logKVValue(kv[index+1]))
+		} else {
+			sb.WriteString(logKVValue(kv[index]))
+		}
+	}
+
+	return sb.String()
+}
+
+func logKVValue(v interface{}) string {
+	text := fmt.Sprint(v)
+	if text == "" || strings.ContainsAny(text, " \t\r\n\"=") {
+		return strconv.Quote(text)
+	}
+
+	return text
+}
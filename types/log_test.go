@@ -0,0 +1,10 @@
+package types
+
+import "testing"
+
+func TestLogKVText(t *testing.T) {
+	text := LogKVText("http request", "rid", 1, "path", "/api/login", "agent", "curl 7.0", "odd")
+	if text != `http request rid=1 path=/api/login agent="curl 7.0" odd` {
+		t.Fatal("invalid text:", text)
+	}
+}
@@ -5,6 +5,11 @@ import (
 	"strings"
 )
 
+const (
+	LoginLockTypeIP      = "ip"
+	LoginLockTypeAccount = "account"
+)
+
 type Login struct {
 	Token   string `json:"token" note:"接口访问凭证" example:"7faf10b0bde847c9905c93966594c82b"`
 	Account string `json:"account" required:"true" note:"账号名称"`
@@ -42,4 +47,18 @@ type LoginAccount struct {
 	Account   string   `json:"account" note:"账号名称"`
 	Name      string   `json:"name" note:"用户姓名"`
 	LoginTime DateTime `json:"loginTime" note:"登陆时间"`
+	Roles     []string `json:"roles" note:"角色"`
+}
+
+type LoginLock struct {
+	Type       string   `json:"type" note:"类型: ip-客户端IP; account-账号"`
+	Value      string   `json:"value" note:"IP地址或账号"`
+	Failures   int      `json:"failures" note:"锁定时的失败次数"`
+	LockTime   DateTime `json:"lockTime" note:"锁定时间"`
+	UnlockTime DateTime `json:"unlockTime" note:"解锁时间"`
+}
+
+type LoginLockFilter struct {
+	Type  string `json:"type" note:"类型: ip-客户端IP; account-账号; 为空时清除所有锁定"`
+	Value string `json:"value" note:"IP地址或账号, 为空时清除该类型的所有锁定"`
 }
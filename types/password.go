@@ -0,0 +1,162 @@
+package types
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/pbkdf2"
+	"hash"
+	"strconv"
+	"strings"
+)
+
+const (
+	PasswordHashBcrypt       = "bcrypt"        // $2a$10$...
+	PasswordHashArgon2id     = "argon2id"      // $argon2id$v=19$m=65536,t=1,p=4$<salt>$<hash>
+	PasswordHashPbkdf2Sha256 = "pbkdf2-sha256" // $pbkdf2-sha256$<iterations>$<salt>$<hash>
+	PasswordHashPbkdf2Sha512 = "pbkdf2-sha512" // $pbkdf2-sha512$<iterations>$<salt>$<hash>
+)
+
+const (
+	passwordSaltSize         = 16
+	passwordArgon2Memory     = 64 * 1024
+	passwordArgon2Time       = 1
+	passwordArgon2Threads    = 4
+	passwordArgon2KeySize    = 32
+	passwordArgon2MaxMemory  = 1024 * 1024 // 1GB, 单位KB
+	passwordArgon2MaxTime    = 64
+	passwordPbkdf2Iterations = 100000
+)
+
+// 生成密码的哈希值, algorithm为空时使用bcrypt
+func HashPassword(password, algorithm string) (string, error) {
+	switch strings.ToLower(algorithm) {
+	case "", PasswordHashBcrypt:
+		hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hashed), nil
+	case PasswordHashArgon2id:
+		salt, err := newPasswordSalt()
+		if err != nil {
+			return "", err
+		}
+		key := argon2.IDKey([]byte(password), salt, passwordArgon2Time, passwordArgon2Memory, passwordArgon2Threads, passwordArgon2KeySize)
+		return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s", PasswordHashArgon2id, argon2.Version,
+			passwordArgon2Memory, passwordArgon2Time, passwordArgon2Threads,
+			base64.RawStdEncoding.EncodeToString(salt),
+			base64.RawStdEncoding.EncodeToString(key)), nil
+	case PasswordHashPbkdf2Sha256, PasswordHashPbkdf2Sha512:
+		salt, err := newPasswordSalt()
+		if err != nil {
+			return "", err
+		}
+		h, size := pbkdf2Hash(algorithm)
+		key := pbkdf2.Key([]byte(password), salt, passwordPbkdf2Iterations, size, h)
+		return fmt.Sprintf("$%s$%d$%s$%s", strings.ToLower(algorithm), passwordPbkdf2Iterations,
+			base64.RawStdEncoding.EncodeToString(salt),
+			base64.RawStdEncoding.EncodeToString(key)), nil
+	default:
+		return "", fmt.Errorf("unsupported hash algorithm: %s", algorithm)
+	}
+}
+
+// 验证密码, hashed为配置中的密码, 根据前缀识别哈希算法, 无法识别时视为明文
+func VerifyPassword(hashed, password string) bool {
+	if strings.HasPrefix(hashed, "$2a$") ||
+		strings.HasPrefix(hashed, "$2b$") ||
+		strings.HasPrefix(hashed, "$2y$") {
+		return bcrypt.CompareHashAndPassword([]byte(hashed), []byte(password)) == nil
+	} else if strings.HasPrefix(hashed, "$"+PasswordHashArgon2id+"$") {
+		return verifyArgon2Password(hashed, password)
+	} else if strings.HasPrefix(hashed, "$"+PasswordHashPbkdf2Sha256+"$") ||
+		strings.HasPrefix(hashed, "$"+PasswordHashPbkdf2Sha512+"$") {
+		return verifyPbkdf2Password(hashed, password)
+	}
+
+	return subtle.ConstantTimeCompare([]byte(hashed), []byte(password)) == 1
+}
+
+func verifyArgon2Password(hashed, password string) bool {
+	// $argon2id$v=19$m=65536,t=1,p=4$<salt>$<hash>
+	items := strings.Split(hashed, "$")
+	if len(items) != 6 {
+		return false
+	}
+
+	var version int
+	_, err := fmt.Sscanf(items[2], "v=%d", &version)
+	if err != nil || version != argon2.Version {
+		return false
+	}
+	var memory, passes uint32
+	var threads uint8
+	_, err = fmt.Sscanf(items[3], "m=%d,t=%d,p=%d", &memory, &passes, &threads)
+	if err != nil {
+		return false
+	}
+	// 参数无效时argon2.IDKey会panic, 参数过大时耗尽资源
+	if passes < 1 || passes > passwordArgon2MaxTime || threads < 1 || memory > passwordArgon2MaxMemory {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(items[4])
+	if err != nil {
+		return false
+	}
+	key, err := base64.RawStdEncoding.DecodeString(items[5])
+	if err != nil || len(key) < 1 {
+		return false
+	}
+
+	actual := argon2.IDKey([]byte(password), salt, passes, memory, threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(actual, key) == 1
+}
+
+func verifyPbkdf2Password(hashed, password string) bool {
+	// $pbkdf2-sha256$<iterations>$<salt>$<hash>
+	items := strings.Split(hashed, "$")
+	if len(items) != 5 {
+		return false
+	}
+
+	iterations, err := strconv.Atoi(items[2])
+	if err != nil || iterations < 1 {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(items[3])
+	if err != nil {
+		return false
+	}
+	key, err := base64.RawStdEncoding.DecodeString(items[4])
+	if err != nil || len(key) < 1 {
+		return false
+	}
+
+	h, _ := pbkdf2Hash(items[1])
+	actual := pbkdf2.Key([]byte(password), salt, iterations, len(key), h)
+	return subtle.ConstantTimeCompare(actual, key) == 1
+}
+
+func pbkdf2Hash(algorithm string) (func() hash.Hash, int) {
+	if strings.ToLower(algorithm) == PasswordHashPbkdf2Sha512 {
+		return sha512.New, sha512.Size
+	}
+
+	return sha256.New, sha256.Size
+}
+
+func newPasswordSalt() ([]byte, error) {
+	salt := make([]byte, passwordSaltSize)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	return salt, nil
+}
@@ -0,0 +1,46 @@
+package types
+
+import "testing"
+
+func TestVerifyPassword(t *testing.T) {
+	algorithms := []string{
+		PasswordHashBcrypt,
+		PasswordHashArgon2id,
+		PasswordHashPbkdf2Sha256,
+		PasswordHashPbkdf2Sha512,
+	}
+	for _, algorithm := range algorithms {
+		hashed, err := HashPassword("P@ssw0rd", algorithm)
+		if err != nil {
+			t.Fatal(algorithm, ": ", err)
+		}
+		t.Log(hashed)
+
+		if !VerifyPassword(hashed, "P@ssw0rd") {
+			t.Fatal(algorithm, ": password should be matched")
+		}
+		if VerifyPassword(hashed, "password") {
+			t.Fatal(algorithm, ": password should not be matched")
+		}
+	}
+
+	if !VerifyPassword("1", "1") {
+		t.Fatal("plaintext password should be matched")
+	}
+	if VerifyPassword("1", "2") {
+		t.Fatal("plaintext password should not be matched")
+	}
+}
+
+func TestVerifyPassword_Argon2Params(t *testing.T) {
+	hashes := []string{
+		"$argon2id$v=19$m=65536,t=0,p=4$c2FsdHNhbHRzYWx0c2FsdA$aGFzaGhhc2hoYXNoaGFzaGhhc2hoYXNoaGFzaGhhc2g",
+		"$argon2id$v=19$m=65536,t=1,p=0$c2FsdHNhbHRzYWx0c2FsdA$aGFzaGhhc2hoYXNoaGFzaGhhc2hoYXNoaGFzaGhhc2g",
+		"$argon2id$v=19$m=4294967295,t=1,p=4$c2FsdHNhbHRzYWx0c2FsdA$aGFzaGhhc2hoYXNoaGFzaGhhc2hoYXNoaGFzaGhhc2g",
+	}
+	for _, hashed := range hashes {
+		if VerifyPassword(hashed, "P@ssw0rd") {
+			t.Fatal("invalid argon2 parameters should not be matched:", hashed)
+		}
+	}
+}
@@ -14,6 +14,8 @@ type HttpPath interface {
 	IsWebSocket() bool
 	IsShortenPath() bool
 	RawPath() string
+	Permission() string // 访问所需角色, 为空时不限制
+	Validation() bool   // 是否根据接口定义验证输入
 	TokenUI() func() []TokenUI
 	TokenCreate() func(items []TokenAuth, a Assistant) (string, ErrorCode, error)
 
@@ -21,6 +23,8 @@ type HttpPath interface {
 	SetWebSocket(webSocket bool) HttpPath
 	SetTokenPlace(tokenPlace int) HttpPath
 	SetTokenType(tokenType int) HttpPath
+	SetPermission(permission string) HttpPath
+	SetValidation(validation bool) HttpPath
 	SetTokenUI(tokenUI func() []TokenUI) HttpPath
 	SetTokenCreate(tokenCreate func(items []TokenAuth, a Assistant) (string, ErrorCode, error)) HttpPath
 }
@@ -32,6 +36,8 @@ type httpPath struct {
 	tokenPlace    int
 	isWebSocket   bool
 	isShortenPath bool
+	permission    string
+	validation    bool
 
 	tokenUI     func() []TokenUI
 	tokenCreate func(items []TokenAuth, a Assistant) (string, ErrorCode, error)
@@ -65,6 +71,14 @@ func (s *httpPath) RawPath() string {
 	return s.rawPath
 }
 
+func (s *httpPath) Permission() string {
+	return s.permission
+}
+
+func (s *httpPath) Validation() bool {
+	return s.validation
+}
+
 func (s *httpPath) TokenUI() func() []TokenUI {
 	return s.tokenUI
 }
@@ -93,6 +107,16 @@ func (s *httpPath) SetTokenType(tokenType int) HttpPath {
 	return s
 }
 
+func (s *httpPath) SetPermission(permission string) HttpPath {
+	s.permission = permission
+	return s
+}
+
+func (s *httpPath) SetValidation(validation bool) HttpPath {
+	s.validation = validation
+	return s
+}
+
 func (s *httpPath) SetTokenUI(tokenUI func() []TokenUI) HttpPath {
 	s.tokenUI = tokenUI
 	return s
@@ -118,6 +142,8 @@ type Path struct {
 	DefaultTokenType   int
 	DefaultTokenPlace  int
 	DefaultShortenUrl  bool
+	DefaultPermission  string
+	DefaultValidation  bool
 	DefaultTokenUI     func() []TokenUI
 	DefaultTokenCreate func(items []TokenAuth, a Assistant) (string, ErrorCode, error)
 }
@@ -135,6 +161,8 @@ func (s *Path) New(path string) HttpPath {
 		tokenPlace:    s.DefaultTokenPlace,
 		isWebSocket:   false,
 		isShortenPath: s.DefaultShortenUrl,
+		permission:    s.DefaultPermission,
+		validation:    s.DefaultValidation,
 		tokenUI:       s.DefaultTokenUI,
 		tokenCreate:   s.DefaultTokenCreate,
 	}
@@ -11,8 +11,9 @@ type Result struct {
 }
 
 type ResultError struct {
-	Summary string `json:"summary" note:"描述信息, 用于错误信息提示"`
-	Detail  string `json:"detail" note:"详细信息, 用于错误排查"`
+	Summary string           `json:"summary" note:"描述信息, 用于错误信息提示"`
+	Detail  string           `json:"detail" note:"详细信息, 用于错误排查"`
+	Fields  ValidationErrors `json:"fields,omitempty" note:"输入验证失败的字段, 仅输入验证失败时有效"`
 }
 
 func (s *Result) Marshal() ([]byte, error) {
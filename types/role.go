@@ -0,0 +1,24 @@
+package types
+
+import "strings"
+
+const (
+	RoleAdmin   = "admin"   // 管理员, 拥有所有权限
+	RoleSite    = "site"    // 网站管理: 上传及删除网站
+	RoleService = "service" // 服务管理: 重启及更新服务
+)
+
+// 判断角色是否满足访问所需的角色(permission), permission为空时不限制
+func HasPermission(roles []string, permission string) bool {
+	if len(permission) < 1 {
+		return true
+	}
+
+	for _, role := range roles {
+		if strings.EqualFold(role, RoleAdmin) || strings.EqualFold(role, permission) {
+			return true
+		}
+	}
+
+	return false
+}
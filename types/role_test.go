@@ -0,0 +1,18 @@
+package types
+
+import "testing"
+
+func TestHasPermission(t *testing.T) {
+	if !HasPermission(nil, "") {
+		t.Fatal("empty permission should be allowed")
+	}
+	if HasPermission([]string{RoleSite}, RoleService) {
+		t.Fatal("role 'site' should not have permission 'service'")
+	}
+	if !HasPermission([]string{RoleSite}, RoleSite) {
+		t.Fatal("role 'site' should have permission 'site'")
+	}
+	if !HasPermission([]string{"Admin"}, RoleService) {
+		t.Fatal("role 'admin' should have all permissions")
+	}
+}
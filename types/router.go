@@ -2,18 +2,70 @@ package types
 
 import "net/http"
 
+const (
+	// 当前请求匹配的路由路径(HttpPath), 由路由器在处理前设置, 可通过Assistant.Get获取
+	RouterKeyHttpPath = "router.httpPath"
+	// 当前请求凭证对应的账号, 由凭证验证函数(preHandle)在验证通过后设置, 可通过Assistant.Get获取
+	RouterKeyAccount = "router.account"
+)
+
 type DocHandle func(doc Doc, method string, path HttpPath)
 type RouterHandle func(http.ResponseWriter, *http.Request, Params, Assistant)
 type RouterPreHandle func(http.ResponseWriter, *http.Request, Params, Assistant) bool
 
+// 中间件, 包装下一个处理函数, 用于凭证验证、审计及限流等公共逻辑
+// 执行顺序: 全局中间件 -> 分组中间件 -> 路由中间件 -> preHandle -> routerHandle
+type RouterMiddleware func(next RouterHandle) RouterHandle
+
 type Router interface {
-	GET(path HttpPath, preHandle RouterPreHandle, routerHandle RouterHandle, docHandle DocHandle)
-	POST(path HttpPath, preHandle RouterPreHandle, routerHandle RouterHandle, docHandle DocHandle)
+	GET(path HttpPath, preHandle RouterPreHandle, routerHandle RouterHandle, docHandle DocHandle, middlewares ...RouterMiddleware)
+	POST(path HttpPath, preHandle RouterPreHandle, routerHandle RouterHandle, docHandle DocHandle, middlewares ...RouterMiddleware)
+	PUT(path HttpPath, preHandle RouterPreHandle, routerHandle RouterHandle, docHandle DocHandle, middlewares ...RouterMiddleware)
+	DELETE(path HttpPath, preHandle RouterPreHandle, routerHandle RouterHandle, docHandle DocHandle, middlewares ...RouterMiddleware)
+	PATCH(path HttpPath, preHandle RouterPreHandle, routerHandle RouterHandle, docHandle DocHandle, middlewares ...RouterMiddleware)
+	HEAD(path HttpPath, preHandle RouterPreHandle, routerHandle RouterHandle, docHandle DocHandle, middlewares ...RouterMiddleware)
+	OPTIONS(path HttpPath, preHandle RouterPreHandle, routerHandle RouterHandle, docHandle DocHandle, middlewares ...RouterMiddleware)
 
 	// path must end with "/*filepath",
 	// example: ServeFiles("/src/*filepath", http.Dir("/var/www"), nil)
-	ServeFiles(path HttpPath, preHandle RouterPreHandle, root http.FileSystem, docHandle DocHandle)
+	ServeFiles(path HttpPath, preHandle RouterPreHandle, root http.FileSystem, docHandle DocHandle, middlewares ...RouterMiddleware)
+
+	// 添加中间件, 根路由器: 作用于所有路由; 分组: 作用于之后在该分组内注册的路由
+	Use(middlewares ...RouterMiddleware)
+
+	// 创建分组, 分组内注册的路由原始路径必须以prefix开头(相对于上级分组), 并依次使用上级及本分组的中间件
+	// example: Group("/opt.api", tokenChecker).POST(path.New("/info"), nil, handle, doc)
+	Group(prefix string, middlewares ...RouterMiddleware) Router
+
+	// 创建路径分组, 分组内注册的路由使用path创建路径, 使用preHandle作为预处理函数,
+	// 指定catalogs时, 文档函数中通过doc.AddCatalog创建的目录位于catalogs指定的文档目录之下
+	// example: PathGroup(path, tokenChecker, "管理平台接口").POST("/info", handle, doc)
+	PathGroup(path Path, preHandle RouterPreHandle, catalogs ...string) RouterGroup
 
 	// api document
 	Document() Doc
 }
+
+type RouterGroup interface {
+	GET(path string, routerHandle RouterHandle, docHandle DocHandle, middlewares ...RouterMiddleware)
+	POST(path string, routerHandle RouterHandle, docHandle DocHandle, middlewares ...RouterMiddleware)
+	PUT(path string, routerHandle RouterHandle, docHandle DocHandle, middlewares ...RouterMiddleware)
+	DELETE(path string, routerHandle RouterHandle, docHandle DocHandle, middlewares ...RouterMiddleware)
+	PATCH(path string, routerHandle RouterHandle, docHandle DocHandle, middlewares ...RouterMiddleware)
+	HEAD(path string, routerHandle RouterHandle, docHandle DocHandle, middlewares ...RouterMiddleware)
+	OPTIONS(path string, routerHandle RouterHandle, docHandle DocHandle, middlewares ...RouterMiddleware)
+
+	// 使用自定义路径注册, 路径由NewPath创建并可修改其设置
+	// example: Handle("GET", NewPath("/notify").SetWebSocket(true), handle, doc)
+	Handle(method string, path HttpPath, routerHandle RouterHandle, docHandle DocHandle, middlewares ...RouterMiddleware)
+
+	// 创建分组内路径, 即分组路径前缀+path, 并使用分组的凭证等默认设置
+	NewPath(path string) HttpPath
+
+	// 创建子分组, 路径前缀追加prefix, 文档目录追加catalogs, 并使用相同的预处理函数
+	Group(prefix string, catalogs ...string) RouterGroup
+
+	// 创建访问需要指定角色的分组(路径前缀及文档目录不变)
+	// example: Require(types.RoleSite).POST("/upload", handle, doc)
+	Require(permission string) RouterGroup
+}
@@ -1,5 +1,7 @@
 package types
 
+import "io"
+
 type Server interface {
 	ServiceName() string
 	Interactive() bool
@@ -13,6 +15,11 @@ type Server interface {
 	Status() (ServerStatus, error)
 }
 
+// Server及Host的可选扩展, 导出进程内注册的接口文档(format: markdown或html), 不启动服务
+type ServerDocExporter interface {
+	ExportDoc(w io.Writer, format, title string) error
+}
+
 type ServerStatus byte
 
 const (
@@ -9,6 +9,7 @@ import (
 const (
 	WSOptUserLogin  = 101 // 用户登陆
 	WSOptUserLogout = 102 // 用户注销
+	WSOptLoginLock  = 103 // 登陆锁定(失败次数过多)
 
 	WSOptSiteUpload      = 111 // 上传并发布后台服务管理网站
 	WSDocSiteUpload      = 112 // 上传并发布后台接口文档网站
@@ -17,6 +18,10 @@ const (
 	WSWebappSiteUpload   = 115 // 上传并发布后应用网站
 	WSWebappSiteDelete   = 116 // 删除应用网站
 	WSCustomSiteUpload   = 119 // 上传并发布自定义网站
+
+	WSLogTail          = 121 // 日志实时输出
+	WSLogTailSubscribe = 122 // 订阅日志实时输出(客户端发送, 需管理员角色)
+	WSLogLevelChanged  = 123 // 日志级别变更
 )
 
 type SocketMessage struct {
@@ -44,10 +49,13 @@ type SocketChannel interface {
 }
 
 type innerSocketChannel struct {
+	mutex sync.RWMutex
+
 	channel   chan *SocketMessage
 	element   *list.Element
 	container *innerSocketChannelCollection
 	token     *Token
+	closed    bool
 }
 
 func (s *innerSocketChannel) Token() *Token {
@@ -59,6 +67,13 @@ func (s *innerSocketChannel) Container() SocketChannelCollection {
 }
 
 func (s *innerSocketChannel) Write(message *SocketMessage) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.closed {
+		return
+	}
+
 	select {
 	case s.channel <- message:
 	default:
@@ -74,6 +89,13 @@ func (s *innerSocketChannel) getElement() *list.Element {
 }
 
 func (s *innerSocketChannel) close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.closed = true
 	close(s.channel)
 }
 
@@ -88,11 +110,22 @@ type SocketChannelCollection interface {
 	AddFilter(filter func(message *SocketMessage, channel SocketChannel, token *Token) bool)
 }
 
+// 以下为SocketChannelCollection的可选扩展, NewSocketChannelCollection创建的实例均已实现
+
+type SocketChannelMessages interface {
+	Messages() SocketMessageRegistry // 消息类型注册表
+}
+
+type SocketChannelShutdown interface {
+	Shutdown() // 关闭所有通道(读取通道时返回关闭状态), 之后新建的通道也将立即关闭
+}
+
 func NewSocketChannelCollection() SocketChannelCollection {
 	instance := &innerSocketChannelCollection{}
 	instance.channels = list.New()
 	instance.readers = make([]func(message *SocketMessage, channel SocketChannel), 0)
 	instance.filters = make([]func(message *SocketMessage, channel SocketChannel, token *Token) bool, 0)
+	instance.messages = NewSocketMessageRegistry()
 
 	return instance
 }
@@ -105,6 +138,8 @@ type innerSocketChannelCollection struct {
 	filters        []func(message *SocketMessage, channel SocketChannel, token *Token) bool
 	newListener    func(channel SocketChannel)
 	removeListener func(channel SocketChannel)
+	shutdown       bool
+	messages       SocketMessageRegistry
 }
 
 func (s *innerSocketChannelCollection) OnlineUsers() []*OnlineUser {
@@ -160,6 +195,10 @@ func (s *innerSocketChannelCollection) NewChannel(token *Token) SocketChannel {
 		s.newListener(instance)
 	}
 
+	if s.shutdown {
+		instance.close()
+	}
+
 	return instance
 }
 
@@ -202,6 +241,21 @@ func (s *innerSocketChannelCollection) Write(message *SocketMessage, token *Toke
 	}
 }
 
+func (s *innerSocketChannelCollection) Shutdown() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.shutdown = true
+	for e := s.channels.Front(); e != nil; {
+		ev, ok := e.Value.(SocketChannel)
+		if ok {
+			ev.close()
+		}
+
+		e = e.Next()
+	}
+}
+
 func (s *innerSocketChannelCollection) AddReader(reader func(message *SocketMessage, channel SocketChannel)) {
 	if reader == nil {
 		return
@@ -214,9 +268,14 @@ func (s *innerSocketChannelCollection) AddReader(reader func(message *SocketMess
 }
 
 func (s *innerSocketChannelCollection) Read(message *SocketMessage, channel SocketChannel) {
-	count := len(s.readers)
+	s.Lock()
+	readers := make([]func(message *SocketMessage, channel SocketChannel), len(s.readers))
+	copy(readers, s.readers)
+	s.Unlock()
+
+	count := len(readers)
 	for i := 0; i < count; i++ {
-		reader := s.readers[0]
+		reader := readers[i]
 		if reader == nil {
 			continue
 		}
@@ -243,10 +302,14 @@ func (s *innerSocketChannelCollection) AddFilter(filter func(message *SocketMess
 	s.filters = append(s.filters, filter)
 }
 
+func (s *innerSocketChannelCollection) Messages() SocketMessageRegistry {
+	return s.messages
+}
+
 func (s *innerSocketChannelCollection) filter(message *SocketMessage, channel SocketChannel, token *Token) bool {
 	count := len(s.filters)
 	for i := 0; i < count; i++ {
-		filter := s.filters[0]
+		filter := s.filters[i]
 		if filter == nil {
 			continue
 		}
@@ -0,0 +1,144 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+const (
+	SocketDirectionServerToClient = 1 // 服务端推送至客户端
+	SocketDirectionClientToServer = 2 // 客户端发送至服务端
+	SocketDirectionBoth           = 3 // 双向
+)
+
+type SocketMessageType struct {
+	ID        int         `json:"id" note:"消息标识"`
+	Name      string      `json:"name" note:"消息名称"`
+	Direction int         `json:"direction" note:"方向: 1-服务端推送至客户端; 2-客户端发送至服务端; 3-双向"`
+	Data      interface{} `json:"data" note:"消息内容示例"`
+}
+
+func (s *SocketMessageType) FromServer() bool {
+	return s.Direction&SocketDirectionServerToClient != 0
+}
+
+func (s *SocketMessageType) FromClient() bool {
+	return s.Direction&SocketDirectionClientToServer != 0
+}
+
+// websocket消息类型注册表
+type SocketMessageRegistry interface {
+	// 注册消息类型, 标识已存在时替换, data为消息内容(SocketMessage.Data)示例
+	// 需在映射路由前注册, 否则不会出现在接口文档中
+	Register(id int, name string, direction int, data interface{})
+	Get(id int) *SocketMessageType
+	// 所有消息类型, 按标识排序
+	Items() []*SocketMessageType
+	// 检查客户端发送的消息, 未注册或不允许客户端发送时返回错误
+	CheckClient(message *SocketMessage) error
+	// 严格模式下拒绝CheckClient返回错误的消息, 否则仅记录日志并继续处理, 默认为false
+	SetStrict(strict bool)
+	Strict() bool
+	// 将消息类型作为websocket接口的输入(客户端发送)或输出(服务端推送)变体添加到文档中
+	Doc(function Function)
+}
+
+func NewSocketMessageRegistry() SocketMessageRegistry {
+	return &innerSocketMessageRegistry{
+		items: make(map[int]*SocketMessageType),
+	}
+}
+
+type innerSocketMessageRegistry struct {
+	sync.RWMutex
+
+	items  map[int]*SocketMessageType
+	strict bool
+}
+
+func (s *innerSocketMessageRegistry) Register(id int, name string, direction int, data interface{}) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.items[id] = &SocketMessageType{
+		ID:        id,
+		Name:      name,
+		Direction: direction,
+		Data:      data,
+	}
+}
+
+func (s *innerSocketMessageRegistry) Get(id int) *SocketMessageType {
+	s.RLock()
+	defer s.RUnlock()
+
+	item, ok := s.items[id]
+	if !ok {
+		return nil
+	}
+
+	return item
+}
+
+func (s *innerSocketMessageRegistry) Items() []*SocketMessageType {
+	s.RLock()
+	defer s.RUnlock()
+
+	items := make([]*SocketMessageType, 0, len(s.items))
+	for _, item := range s.items {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].ID < items[j].ID
+	})
+
+	return items
+}
+
+func (s *innerSocketMessageRegistry) CheckClient(message *SocketMessage) error {
+	if message == nil {
+		return fmt.Errorf("message is nil")
+	}
+
+	item := s.Get(message.ID)
+	if item == nil {
+		return fmt.Errorf("unknown message id: %d", message.ID)
+	}
+	if !item.FromClient() {
+		return fmt.Errorf("message id %d (%s) is not allowed from client", message.ID, item.Name)
+	}
+
+	return nil
+}
+
+func (s *innerSocketMessageRegistry) SetStrict(strict bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.strict = strict
+}
+
+func (s *innerSocketMessageRegistry) Strict() bool {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.strict
+}
+
+func (s *innerSocketMessageRegistry) Doc(function Function) {
+	if function == nil {
+		return
+	}
+
+	for _, item := range s.Items() {
+		name := fmt.Sprintf("%d-%s", item.ID, item.Name)
+		example := &SocketMessage{ID: item.ID, Data: item.Data}
+		if item.FromServer() {
+			function.AddOutputVariant("id", item.ID, name, example)
+		}
+		if item.FromClient() {
+			function.AddInputVariant("id", item.ID, name, example)
+		}
+	}
+}
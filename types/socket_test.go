@@ -0,0 +1,87 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSocketChannelCollection_Shutdown(t *testing.T) {
+	chs := NewSocketChannelCollection()
+	ch := chs.NewChannel(nil)
+
+	chs.(SocketChannelShutdown).Shutdown()
+	_, ok := <-ch.Read()
+	if ok {
+		t.Fatal("channel should be closed after shutdown")
+	}
+
+	// write to or remove closed channel should not panic
+	ch.Write(&SocketMessage{ID: 1})
+	chs.Write(&SocketMessage{ID: 1}, nil)
+	chs.Remove(ch)
+
+	newCh := chs.NewChannel(nil)
+	_, ok = <-newCh.Read()
+	if ok {
+		t.Fatal("new channel should be closed after shutdown")
+	}
+}
+
+func TestSocketChannelCollection_ReadersAndFilters(t *testing.T) {
+	chs := NewSocketChannelCollection()
+	read := make(chan int, 2)
+	chs.AddReader(func(message *SocketMessage, channel SocketChannel) { read <- 1 })
+	chs.AddReader(func(message *SocketMessage, channel SocketChannel) { read <- 2 })
+
+	chs.Read(&SocketMessage{ID: 1}, nil)
+	sum := 0
+	for i := 0; i < 2; i++ {
+		select {
+		case v := <-read:
+			sum += v
+		case <-time.After(time.Second):
+			t.Fatal("all readers should be called")
+		}
+	}
+	if sum != 3 {
+		t.Fatal("each reader should be called once, but got", sum)
+	}
+
+	chs.AddFilter(func(message *SocketMessage, channel SocketChannel, token *Token) bool { return false })
+	chs.AddFilter(func(message *SocketMessage, channel SocketChannel, token *Token) bool { return message.ID == 2 })
+	ch := chs.NewChannel(nil)
+	chs.Write(&SocketMessage{ID: 2}, nil)
+	chs.Write(&SocketMessage{ID: 3}, nil)
+	msg := <-ch.Read()
+	if msg.ID != 3 {
+		t.Fatal("message should be filtered by the second filter, but got", msg.ID)
+	}
+}
+
+func TestSocketMessageRegistry_CheckClient(t *testing.T) {
+	messages := NewSocketChannelCollection().(SocketChannelMessages).Messages()
+	messages.Register(201, "ping", SocketDirectionBoth, nil)
+	messages.Register(101, "login", SocketDirectionServerToClient, &OnlineUser{})
+
+	if err := messages.CheckClient(&SocketMessage{ID: 201}); err != nil {
+		t.Fatal(err)
+	}
+	if messages.CheckClient(&SocketMessage{ID: 101}) == nil {
+		t.Fatal("message from server only should be rejected")
+	}
+	if messages.CheckClient(&SocketMessage{ID: 999}) == nil {
+		t.Fatal("unknown message should be rejected")
+	}
+	if messages.Strict() {
+		t.Fatal("registry should not be strict by default")
+	}
+	messages.SetStrict(true)
+	if !messages.Strict() {
+		t.Fatal("registry should be strict")
+	}
+
+	items := messages.Items()
+	if len(items) != 2 || items[0].ID != 101 {
+		t.Fatal("items should be sorted by id")
+	}
+}
@@ -0,0 +1,25 @@
+package types
+
+import "time"
+
+const (
+	// 消息格式: 长度(4字节, 大端) + 内容
+	TcpMessageHeadSize       = 4
+	TcpMessageDefaultMaxSize = 1024 * 1024
+)
+
+type TcpConn interface {
+	ID() uint64
+	RemoteAddr() string // 客户端地址, 位于代理服务器之后时为真实的客户端地址
+	LocalAddr() string
+	ConnectTime() time.Time
+	IsTls() bool
+
+	// 发送一条消息, 自动添加长度头部
+	Write(message []byte) error
+	Close() error
+
+	Set(key string, val interface{})
+	Get(key string) (interface{}, bool)
+	Del(key string) bool
+}
@@ -0,0 +1,211 @@
+package types
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// 刷新激活时间后延迟保存, 合并短时间内的多次修改
+	tokenFlushDelay = 5 * time.Second
+)
+
+// 凭证数据保存在filePath指定的JSON文件中, 服务重启后依然有效, 保存的数据类型须为*Token
+// expiration: 凭证过期时间, 单位分钟, 0表示永不过期
+func NewFileTokenDatabase(log Log, expiration int64, name, filePath string) (TokenDatabaseCloser, error) {
+	instance := &fileTokenDatabase{filePath: filePath}
+	instance.SetLog(log)
+	instance.memoryTokenDatabase = newMemoryTokenDatabase(expiration, name)
+
+	err := instance.load()
+	if err != nil {
+		return nil, err
+	}
+	go instance.sweep(instance.onSwept)
+
+	return instance, nil
+}
+
+type fileTokenItem struct {
+	Data       json.RawMessage `json:"data"`
+	ActiveTime time.Time       `json:"activeTime"`
+}
+
+type fileTokenDatabase struct {
+	Base
+	*memoryTokenDatabase
+
+	filePath string
+	dirty    bool
+	flush    *time.Timer
+	closed   bool
+	fmu      sync.Mutex
+}
+
+// 停止后台清理并保存未保存的修改
+func (s *fileTokenDatabase) Close() error {
+	s.memoryTokenDatabase.Close()
+
+	s.fmu.Lock()
+	s.closed = true
+	if s.flush != nil {
+		s.flush.Stop()
+		s.flush = nil
+	}
+	dirty := s.dirty
+	s.fmu.Unlock()
+
+	if dirty {
+		s.save()
+	}
+
+	return nil
+}
+
+func (s *fileTokenDatabase) Set(key string, data interface{}) {
+	s.memoryTokenDatabase.Set(key, data)
+	s.save()
+}
+
+func (s *fileTokenDatabase) Get(key string, delay bool) (interface{}, bool) {
+	data, ok := s.memoryTokenDatabase.Get(key, delay)
+	if ok && delay {
+		s.setDirty()
+	}
+
+	return data, ok
+}
+
+func (s *fileTokenDatabase) Del(key string) bool {
+	ok := s.memoryTokenDatabase.Del(key)
+	if ok {
+		s.save()
+	}
+
+	return ok
+}
+
+func (s *fileTokenDatabase) Permanent(key string, val bool) bool {
+	ok := s.memoryTokenDatabase.Permanent(key, val)
+	if ok {
+		s.setDirty()
+	}
+
+	return ok
+}
+
+// 标记为已修改, 并在tokenFlushDelay后保存
+func (s *fileTokenDatabase) setDirty() {
+	s.fmu.Lock()
+	defer s.fmu.Unlock()
+
+	s.dirty = true
+	if s.flush != nil || s.closed {
+		return
+	}
+	s.flush = time.AfterFunc(tokenFlushDelay, s.onFlush)
+}
+
+func (s *fileTokenDatabase) onFlush() {
+	s.fmu.Lock()
+	s.flush = nil
+	dirty := s.dirty
+	s.fmu.Unlock()
+
+	if dirty {
+		s.save()
+	}
+}
+
+func (s *fileTokenDatabase) onSwept(count int) {
+	s.fmu.Lock()
+	dirty := s.dirty
+	s.fmu.Unlock()
+
+	if count > 0 || dirty {
+		s.save()
+	}
+}
+
+func (s *fileTokenDatabase) load() error {
+	data, err := ioutil.ReadFile(s.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if len(data) < 1 {
+		return nil
+	}
+
+	items := make(map[string]*fileTokenItem)
+	err = json.Unmarshal(data, &items)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	now := time.Now()
+	for k, v := range items {
+		if v == nil {
+			continue
+		}
+		token := &Token{}
+		err = json.Unmarshal(v.Data, token)
+		if err != nil {
+			s.LogWarning("token database(", s.name, ") load item '", k, "' error: ", err)
+			continue
+		}
+		item := &tokenItem{
+			Data:       token,
+			ActiveTime: v.ActiveTime,
+		}
+		if s.expired(item, now) {
+			continue
+		}
+		s.items[k] = item
+	}
+
+	return nil
+}
+
+func (s *fileTokenDatabase) save() {
+	s.fmu.Lock()
+	defer s.fmu.Unlock()
+
+	s.RLock()
+	data, err := json.MarshalIndent(s.items, "", "    ")
+	s.RUnlock()
+	if err != nil {
+		s.LogError("token database(", s.name, ") save error: ", err)
+		return
+	}
+
+	folder := filepath.Dir(s.filePath)
+	err = os.MkdirAll(folder, 0777)
+	if err != nil {
+		s.LogError("token database(", s.name, ") save error: ", err)
+		return
+	}
+
+	tempPath := s.filePath + ".tmp"
+	err = ioutil.WriteFile(tempPath, data, 0600)
+	if err != nil {
+		s.LogError("token database(", s.name, ") save error: ", err)
+		return
+	}
+	err = os.Rename(tempPath, s.filePath)
+	if err != nil {
+		s.LogError("token database(", s.name, ") save error: ", err)
+		return
+	}
+
+	s.dirty = false
+}
@@ -33,6 +33,12 @@ type TokenDatabase interface {
 	Permanent(key string, val bool) bool
 }
 
+// 内置凭证数据库, 不再使用时调用Close停止后台清理
+type TokenDatabaseCloser interface {
+	TokenDatabase
+	Close() error
+}
+
 type TokenAuth struct {
 	Name  string `json:"name" note:"名称"`
 	Value string `json:"value" note:"值"`
@@ -85,7 +91,8 @@ type Token struct {
 	LoginIP     string    `json:"loginIp" note:"用户登陆IP"`
 	LoginTime   time.Time `json:"loginTime" note:"登陆时间"`
 	ActiveTime  time.Time `json:"activeTime" note:"最近激活时间"`
-	Usage       int       `json:"usage" "使用次数"`
+	Usage       int       `json:"usage" note:"使用次数"`
+	Roles       []string  `json:"roles" note:"角色"`
 
 	Ext interface{} `json:"ext" note:"扩展信息"`
 }
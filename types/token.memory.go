@@ -0,0 +1,188 @@
+package types
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	tokenSweepInterval = time.Minute
+)
+
+// expiration: 凭证过期时间, 单位分钟, 0表示永不过期
+func NewMemoryTokenDatabase(expiration int64, name string) TokenDatabaseCloser {
+	instance := newMemoryTokenDatabase(expiration, name)
+	go instance.sweep(nil)
+
+	return instance
+}
+
+func newMemoryTokenDatabase(expiration int64, name string) *memoryTokenDatabase {
+	instance := &memoryTokenDatabase{name: name}
+	instance.expiration = time.Duration(expiration) * time.Minute
+	instance.items = make(map[string]*tokenItem)
+	instance.stop = make(chan struct{})
+
+	return instance
+}
+
+type tokenItem struct {
+	Data       interface{} `json:"data"`
+	ActiveTime time.Time   `json:"activeTime"`
+	Permanent  bool        `json:"-"`
+}
+
+type memoryTokenDatabase struct {
+	sync.RWMutex
+
+	name       string
+	expiration time.Duration
+	items      map[string]*tokenItem
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func (s *memoryTokenDatabase) Name() string {
+	return s.name
+}
+
+// 停止后台清理, 可重复调用
+func (s *memoryTokenDatabase) Close() error {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+
+	return nil
+}
+
+func (s *memoryTokenDatabase) Set(key string, data interface{}) {
+	s.Lock()
+	defer s.Unlock()
+
+	item, ok := s.items[key]
+	if ok {
+		item.Data = data
+		item.ActiveTime = time.Now()
+	} else {
+		s.items[key] = &tokenItem{
+			Data:       data,
+			ActiveTime: time.Now(),
+		}
+	}
+}
+
+// delay: 是否延长有效期(刷新激活时间)
+func (s *memoryTokenDatabase) Get(key string, delay bool) (interface{}, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	item, ok := s.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	now := time.Now()
+	if s.expired(item, now) {
+		delete(s.items, key)
+		return nil, false
+	}
+	if delay {
+		item.ActiveTime = now
+	}
+
+	return item.Data, true
+}
+
+func (s *memoryTokenDatabase) Del(key string) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	_, ok := s.items[key]
+	if ok {
+		delete(s.items, key)
+	}
+
+	return ok
+}
+
+// key: 为空时返回所有数据, 否则返回键值以key开头的数据
+func (s *memoryTokenDatabase) Lst(key string) []interface{} {
+	s.RLock()
+	defer s.RUnlock()
+
+	now := time.Now()
+	items := make([]interface{}, 0)
+	for k, v := range s.items {
+		if s.expired(v, now) {
+			continue
+		}
+		if len(key) > 0 && !strings.HasPrefix(k, key) {
+			continue
+		}
+		items = append(items, v.Data)
+	}
+
+	return items
+}
+
+// val: true-永不过期(如websocket连接中); false-恢复过期检查并刷新激活时间
+func (s *memoryTokenDatabase) Permanent(key string, val bool) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	item, ok := s.items[key]
+	if !ok {
+		return false
+	}
+
+	item.Permanent = val
+	item.ActiveTime = time.Now()
+
+	return true
+}
+
+func (s *memoryTokenDatabase) expired(item *tokenItem, now time.Time) bool {
+	if s.expiration <= 0 {
+		return false
+	}
+	if item.Permanent {
+		return false
+	}
+
+	return now.Sub(item.ActiveTime) > s.expiration
+}
+
+func (s *memoryTokenDatabase) removeExpired() int {
+	s.Lock()
+	defer s.Unlock()
+
+	count := 0
+	now := time.Now()
+	for k, v := range s.items {
+		if s.expired(v, now) {
+			delete(s.items, k)
+			count++
+		}
+	}
+
+	return count
+}
+
+func (s *memoryTokenDatabase) sweep(swept func(count int)) {
+	ticker := time.NewTicker(tokenSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			count := s.removeExpired()
+			if swept != nil {
+				swept(count)
+			}
+		}
+	}
+}
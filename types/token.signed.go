@@ -0,0 +1,137 @@
+package types
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	TokenModeOpaque = ""     // 随机标识, 保存在凭证数据库中
+	TokenModeHmac   = "hmac" // HMAC-SHA256签名
+	TokenModeRsa    = "rsa"  // RSA-SHA256签名
+)
+
+const (
+	TokenAlgorithmHmac = "HS256"
+	TokenAlgorithmRsa  = "RS256"
+)
+
+// 签名凭证的内容(JWT格式)
+type TokenClaims struct {
+	ID        string   `json:"jti" note:"标识ID"`
+	Account   string   `json:"sub" note:"用户账号"`
+	Name      string   `json:"name" note:"用户姓名"`
+	IP        string   `json:"ip,omitempty" note:"绑定的登陆IP, 为空时不限制"`
+	Roles     []string `json:"roles,omitempty" note:"角色"`
+	IssuedAt  int64    `json:"iat" note:"签发时间(unix时间戳)"`
+	ExpiresAt int64    `json:"exp,omitempty" note:"过期时间(unix时间戳), 0表示永不过期"`
+}
+
+func (s *TokenClaims) Expired(now time.Time) bool {
+	if s.ExpiresAt <= 0 {
+		return false
+	}
+
+	return now.Unix() > s.ExpiresAt
+}
+
+type tokenHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+}
+
+// 生成签名凭证, sign: 对数据进行签名的函数
+func NewSignedToken(claims *TokenClaims, algorithm string, sign func(data []byte) ([]byte, error)) (string, error) {
+	if claims == nil {
+		return "", fmt.Errorf("invalid claims: nil")
+	}
+	if sign == nil {
+		return "", fmt.Errorf("invalid sign function: nil")
+	}
+
+	header, err := json.Marshal(&tokenHeader{Algorithm: algorithm, Type: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	sb := &strings.Builder{}
+	sb.WriteString(base64.RawURLEncoding.EncodeToString(header))
+	sb.WriteString(".")
+	sb.WriteString(base64.RawURLEncoding.EncodeToString(payload))
+	signature, err := sign([]byte(sb.String()))
+	if err != nil {
+		return "", err
+	}
+	sb.WriteString(".")
+	sb.WriteString(base64.RawURLEncoding.EncodeToString(signature))
+
+	return sb.String(), nil
+}
+
+// 解析签名凭证并验证签名, 不检查是否过期
+// verify: 验证签名的函数, algorithm为凭证头部声明的算法
+func ParseSignedToken(value string, verify func(algorithm string, data, signature []byte) error) (*TokenClaims, error) {
+	if verify == nil {
+		return nil, fmt.Errorf("invalid verify function: nil")
+	}
+
+	parts := strings.Split(value, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid token format")
+	}
+
+	headerData, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token header: %v", err)
+	}
+	header := &tokenHeader{}
+	err = json.Unmarshal(headerData, header)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token header: %v", err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token signature: %v", err)
+	}
+	err = verify(header.Algorithm, []byte(parts[0]+"."+parts[1]), signature)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token payload: %v", err)
+	}
+	claims := &TokenClaims{}
+	err = json.Unmarshal(payload, claims)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token payload: %v", err)
+	}
+
+	return claims, nil
+}
+
+func HmacSign(secret, data []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write(data)
+
+	return h.Sum(nil)
+}
+
+func HmacVerify(secret, data, signature []byte) error {
+	if !hmac.Equal(HmacSign(secret, data), signature) {
+		return fmt.Errorf("invalid token signature")
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package types
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMemoryTokenDatabase(t *testing.T) {
+	db := newMemoryTokenDatabase(1, "test")
+	defer db.Close()
+	db.Set("a1", &Token{ID: "a1"})
+	db.Set("b1", &Token{ID: "b1"})
+
+	if _, ok := db.Get("a1", false); !ok {
+		t.Fatal("token 'a1' should be existed")
+	}
+	if len(db.Lst("")) != 2 {
+		t.Fatal("expect 2; actual ", len(db.Lst("")))
+	}
+	if len(db.Lst("a")) != 1 {
+		t.Fatal("expect 1; actual ", len(db.Lst("a")))
+	}
+
+	// expired
+	db.items["a1"].ActiveTime = time.Now().Add(-2 * time.Minute)
+	db.items["b1"].ActiveTime = time.Now().Add(-2 * time.Minute)
+	db.Permanent("b1", true)
+	db.items["b1"].ActiveTime = time.Now().Add(-2 * time.Minute)
+	if _, ok := db.Get("a1", true); ok {
+		t.Fatal("token 'a1' should be expired")
+	}
+	if _, ok := db.Get("b1", true); !ok {
+		t.Fatal("permanent token 'b1' should not be expired")
+	}
+
+	db.Permanent("b1", false)
+	db.items["b1"].ActiveTime = time.Now().Add(-2 * time.Minute)
+	if db.removeExpired() != 1 {
+		t.Fatal("token 'b1' should be removed")
+	}
+}
+
+func TestFileTokenDatabase(t *testing.T) {
+	folder, err := ioutil.TempDir("", "wsf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+
+	filePath := filepath.Join(folder, "token.json")
+	db, err := NewFileTokenDatabase(nil, 30, "test", filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Set("a1", &Token{ID: "a1", UserAccount: "admin"})
+	db.Set("b1", &Token{ID: "b1", UserAccount: "test"})
+	db.Del("b1")
+
+	// 刷新激活时间后延迟保存, 关闭时立即保存
+	db.Get("a1", true)
+	if !db.(*fileTokenDatabase).dirty {
+		t.Fatal("database should be dirty after delay")
+	}
+	db.Close()
+	if db.(*fileTokenDatabase).dirty {
+		t.Fatal("dirty data should be saved on close")
+	}
+	db.Close()
+
+	db, err = NewFileTokenDatabase(nil, 30, "test", filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	v, ok := db.Get("a1", false)
+	if !ok {
+		t.Fatal("token 'a1' should be loaded from file")
+	}
+	token, ok := v.(*Token)
+	if !ok {
+		t.Fatal("invalid token type")
+	}
+	if token.UserAccount != "admin" {
+		t.Fatal("expect 'admin'; actual ", token.UserAccount)
+	}
+	if _, ok := db.Get("b1", false); ok {
+		t.Fatal("token 'b1' should be deleted")
+	}
+}
+
+func TestSignedToken(t *testing.T) {
+	secret := []byte("secret")
+	sign := func(data []byte) ([]byte, error) {
+		return HmacSign(secret, data), nil
+	}
+	verify := func(algorithm string, data, signature []byte) error {
+		if algorithm != TokenAlgorithmHmac {
+			t.Fatal("expect '", TokenAlgorithmHmac, "'; actual ", algorithm)
+		}
+		return HmacVerify(secret, data, signature)
+	}
+
+	value, err := NewSignedToken(&TokenClaims{
+		ID:        "a1",
+		Account:   "admin",
+		IP:        "192.168.1.8",
+		ExpiresAt: time.Now().Add(time.Minute).Unix(),
+	}, TokenAlgorithmHmac, sign)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := ParseSignedToken(value, verify)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Account != "admin" || claims.IP != "192.168.1.8" {
+		t.Fatal("invalid claims: ", claims)
+	}
+	if claims.Expired(time.Now()) {
+		t.Fatal("token should not be expired")
+	}
+
+	_, err = ParseSignedToken(value+"a", verify)
+	if err == nil {
+		t.Fatal("token with invalid signature should be rejected")
+	}
+}
@@ -0,0 +1,52 @@
+package types
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	ValidationPlaceHeader = "header"
+	ValidationPlaceQuery  = "query"
+	ValidationPlaceBody   = "body"
+)
+
+// 根据接口定义验证输入, body为请求内容
+type InputValidator func(r *http.Request, body []byte) ValidationErrors
+
+type ValidationError struct {
+	Place   string `json:"place" note:"位置: header-头部; query-参数; body-内容"`
+	Field   string `json:"field" note:"字段名称, 如: items[0].name"`
+	Message string `json:"message" note:"错误信息"`
+}
+
+func (s *ValidationError) Error() string {
+	sb := &strings.Builder{}
+	sb.WriteString(s.Place)
+	if len(s.Field) > 0 {
+		sb.WriteString(".")
+		sb.WriteString(s.Field)
+	}
+	sb.WriteString(": ")
+	sb.WriteString(s.Message)
+
+	return sb.String()
+}
+
+type ValidationErrors []*ValidationError
+
+func (s ValidationErrors) Error() string {
+	items := make([]string, 0, len(s))
+	for _, item := range s {
+		if item == nil {
+			continue
+		}
+		items = append(items, item.Error())
+	}
+
+	return strings.Join(items, "; ")
+}
+
+func (s ValidationErrors) Add(place, field, message string) ValidationErrors {
+	return append(s, &ValidationError{Place: place, Field: field, Message: message})
+}